@@ -25,7 +25,7 @@ type Balance struct {
 }
 
 // GetBalance retrieves the current balance for the account
-func (c *Client) GetBalance(ctx context.Context) (*Balance, error) {
+func (c *Client) GetBalance(ctx context.Context, opts ...RequestOption) (*Balance, error) {
 	if ctx == nil {
 		return nil, &errors.InvalidRequestError{
 			Param: "context",
@@ -33,10 +33,7 @@ func (c *Client) GetBalance(ctx context.Context) (*Balance, error) {
 		}
 	}
 
-	req, err := c.newRequest(ctx, http.MethodGet, "/user/balance", nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+	req := newCall(http.MethodGet, "/user/balance", nil, opts)
 
 	var balance Balance
 	if err := c.do(ctx, req, &balance); err != nil {
@@ -0,0 +1,162 @@
+package deepseek
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// minCompressSize is the smallest request body that is compressed. Smaller
+// bodies gain nothing from gzip.
+const minCompressSize = 1024
+
+// ErrRequestTooLarge is matched by errors.Is for every call rejected by WithMaxRequestSize
+var ErrRequestTooLarge = errors.New("deepseek: request body too large")
+
+// RequestTooLargeError is returned when the encoded request body exceeds the
+// size set with WithMaxRequestSize. Nothing is sent to the API.
+type RequestTooLargeError struct {
+	Size    int64
+	MaxSize int64
+}
+
+func (e *RequestTooLargeError) Error() string {
+	return fmt.Sprintf("deepseek: request body of %d bytes exceeds maximum of %d bytes", e.Size, e.MaxSize)
+}
+
+// Is reports whether target is ErrRequestTooLarge
+func (e *RequestTooLargeError) Is(target error) bool {
+	return target == ErrRequestTooLarge
+}
+
+// IsRetryable returns false; the request must be made smaller first
+func (e *RequestTooLargeError) IsRetryable() bool {
+	return false
+}
+
+// WithRequestCompression gzips request bodies of 1KB or more and asks the API
+// for gzip responses. Compressed responses are always decoded transparently.
+func WithRequestCompression(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.compressRequests = enabled
+	}
+}
+
+// checkRequestSize rejects encoded bodies above the configured maximum. The
+// limit applies to the uncompressed body; a maximum of zero or less disables it.
+func (c *Client) checkRequestSize(body []byte) error {
+	if c.maxRequestSize <= 0 || int64(len(body)) <= c.maxRequestSize {
+		return nil
+	}
+	return &RequestTooLargeError{Size: int64(len(body)), MaxSize: c.maxRequestSize}
+}
+
+// payload is the encoded body of a call. It is compressed once and replayed by
+// every attempt, hedged duplicates included.
+type payload struct {
+	// json is the uncompressed body, as logged
+	json []byte
+	// wire is the body sent, gzipped when gzipped is set
+	wire    []byte
+	gzipped bool
+}
+
+// encodePayload encodes the body of a call, rejects it when it is too large and
+// compresses it when enabled
+func (c *Client) encodePayload(r *Request) (*payload, error) {
+	body, err := encodeBody(r)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	if err := c.checkRequestSize(body); err != nil {
+		return nil, err
+	}
+
+	p := &payload{json: body, wire: body}
+	if c.compressRequests && len(body) >= minCompressSize {
+		if p.wire, err = compressBody(body); err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+		p.gzipped = true
+	}
+	return p, nil
+}
+
+// compressBody gzips body
+func compressBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress request body: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressResponse replaces a gzip encoded response body with its decoded
+// contents. The transport only does this itself when it added Accept-Encoding.
+func decompressResponse(resp *http.Response) error {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		_ = resp.Body.Close()
+		return fmt.Errorf("failed to decompress response body: %v", err)
+	}
+	resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return nil
+}
+
+// gzipBody reads a decompressed response body and closes the underlying one
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	_ = b.Reader.Close()
+	return b.body.Close()
+}
+
+// slotBody holds the queue and concurrency slots of an attempt until its response
+// body has been read to the end or closed, so that unread bodies and open streams
+// count as in flight
+type slotBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *slotBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.done()
+	}
+	return n, err
+}
+
+func (b *slotBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done()
+	return err
+}
+
+// done releases the slots; later calls do nothing
+func (b *slotBody) done() {
+	if b != nil {
+		b.once.Do(b.release)
+	}
+}
@@ -0,0 +1,139 @@
+package deepseek_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestMaxRequestSizeRejectsBeforeSending(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRequestSize(512),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: strings.Repeat("a", 1024)}},
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, deepseek.ErrRequestTooLarge))
+
+	var sizeErr *deepseek.RequestTooLargeError
+	require.True(t, errors.As(err, &sizeErr))
+	assert.Equal(t, int64(512), sizeErr.MaxSize)
+	assert.Greater(t, sizeErr.Size, int64(1024))
+	assert.Equal(t, int32(0), atomic.LoadInt32(&hits))
+}
+
+func TestRequestCompression(t *testing.T) {
+	var encoding, acceptEncoding, content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		acceptEncoding = r.Header.Get("Accept-Encoding")
+
+		zr, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		var req deepseek.ChatCompletionRequest
+		require.NoError(t, json.NewDecoder(zr).Decode(&req))
+		content = req.Messages[0].Content
+
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(`{"id":"chat-1","choices":[{"index":0,"message":{"role":"assistant","content":"ok"}}]}`))
+		_ = zw.Close()
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRequestCompression(true),
+	)
+	require.NoError(t, err)
+
+	prompt := strings.Repeat("long context ", 200)
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: prompt}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "gzip", encoding)
+	assert.Equal(t, "gzip", acceptEncoding)
+	assert.Equal(t, prompt, content)
+	assert.Equal(t, "ok", resp.Choices[0].Message.Content)
+}
+
+func TestSmallRequestsAreNotCompressed(t *testing.T) {
+	var encoding string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRequestCompression(true),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, encoding)
+}
+
+func TestCompressedBodyReplayedOnRetry(t *testing.T) {
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, body)
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"overloaded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRequestCompression(true),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: strings.Repeat("long context ", 200)}},
+	})
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+	assert.Equal(t, bodies[0], bodies[1])
+
+	zr, err := gzip.NewReader(bytes.NewReader(bodies[1]))
+	require.NoError(t, err)
+	_, err = io.ReadAll(zr)
+	assert.NoError(t, err)
+}
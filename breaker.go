@@ -0,0 +1,243 @@
+package deepseek
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerCooldown         = 30 * time.Second
+	defaultBreakerHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is matched by errors.Is for every call rejected by an open circuit breaker
+var ErrCircuitOpen = errors.New("deepseek: circuit breaker is open")
+
+// CircuitOpenError is returned when a call is rejected because the circuit
+// breaker for its endpoint is open
+type CircuitOpenError struct {
+	// BaseURL is the base URL whose circuit is open
+	BaseURL string
+	// Path is the API path whose circuit is open
+	Path string
+	// RetryAfter is the remaining cooldown before the circuit lets a probe through
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("deepseek: circuit breaker for %s%s is open, retry after %s", e.BaseURL, e.Path, e.RetryAfter)
+}
+
+// Is reports whether target is ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// IsRetryable returns true; the call can be retried once the cooldown expires
+func (e *CircuitOpenError) IsRetryable() bool {
+	return true
+}
+
+// isCircuitOpen reports whether err rejected an attempt because its circuit is open
+func isCircuitOpen(err error) bool {
+	_, ok := err.(*CircuitOpenError)
+	return ok
+}
+
+// CircuitState represents the state of a circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every call until the cooldown expires
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe calls through
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreaker configures the per-endpoint circuit breaker. Zero values fall
+// back to the defaults.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit (default 5)
+	FailureThreshold int
+	// Cooldown is how long the circuit stays open before letting probes through (default 30s)
+	Cooldown time.Duration
+	// HalfOpenRequests is the number of concurrent probes allowed while half-open (default 1)
+	HalfOpenRequests int
+}
+
+// WithCircuitBreaker enables a circuit breaker tracked separately for each API path
+// of each base URL. Connection errors and 5xx responses count as failures. While a
+// circuit is open, calls to its path fail fast with a *CircuitOpenError, or move on
+// to the next base URL set with WithBaseURLs.
+func WithCircuitBreaker(cfg CircuitBreaker) ClientOption {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(cfg)
+	}
+}
+
+// CircuitState returns the state of the circuit breaker for an API path such as
+// "/chat/completions" on the client's first base URL. It returns CircuitClosed
+// when the breaker is not enabled.
+func (c *Client) CircuitState(path string) CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.state(c.baseURL, path)
+}
+
+// attemptOutcome classifies the result of an attempt for the circuit breaker
+type attemptOutcome int
+
+const (
+	outcomeNeutral attemptOutcome = iota
+	outcomeSuccess
+	outcomeFailure
+)
+
+// classifyOutcome decides whether an attempt counts as a success or failure of the endpoint
+func classifyOutcome(ctx context.Context, resp *http.Response, err error) attemptOutcome {
+	switch {
+	case err != nil && ctx.Err() != nil:
+		// Cancelled by the caller, says nothing about the endpoint
+		return outcomeNeutral
+	case err != nil:
+		return outcomeFailure
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return outcomeFailure
+	default:
+		return outcomeSuccess
+	}
+}
+
+// circuitBreaker tracks one circuit per API path and base URL
+type circuitBreaker struct {
+	mu       sync.Mutex
+	cfg      CircuitBreaker
+	circuits map[circuitKey]*circuit
+}
+
+// circuitKey identifies the endpoint a circuit tracks
+type circuitKey struct {
+	baseURL string
+	path    string
+}
+
+// circuit is the state of a single endpoint
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+func newCircuitBreaker(cfg CircuitBreaker) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = defaultBreakerFailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = defaultBreakerCooldown
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = defaultBreakerHalfOpenRequests
+	}
+	return &circuitBreaker{
+		cfg:      cfg,
+		circuits: make(map[circuitKey]*circuit),
+	}
+}
+
+func (b *circuitBreaker) circuit(baseURL, path string) *circuit {
+	key := circuitKey{baseURL: baseURL, path: path}
+	cb, ok := b.circuits[key]
+	if !ok {
+		cb = &circuit{}
+		b.circuits[key] = cb
+	}
+	return cb
+}
+
+// allow reports whether a call to path on baseURL may proceed. Every allowed
+// call must be followed by a call to record.
+func (b *circuitBreaker) allow(baseURL, path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb := b.circuit(baseURL, path)
+	if cb.state == CircuitOpen {
+		elapsed := time.Since(cb.openedAt)
+		if elapsed < b.cfg.Cooldown {
+			return &CircuitOpenError{BaseURL: baseURL, Path: path, RetryAfter: b.cfg.Cooldown - elapsed}
+		}
+		cb.state = CircuitHalfOpen
+		cb.probes = 0
+	}
+
+	if cb.state == CircuitHalfOpen {
+		if cb.probes >= b.cfg.HalfOpenRequests {
+			return &CircuitOpenError{BaseURL: baseURL, Path: path}
+		}
+		cb.probes++
+	}
+	return nil
+}
+
+// record updates the circuit of path on baseURL with the outcome of an allowed call
+func (b *circuitBreaker) record(baseURL, path string, outcome attemptOutcome) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb := b.circuit(baseURL, path)
+	switch cb.state {
+	case CircuitOpen:
+		// A call started before the circuit opened; its outcome is stale
+		return
+	case CircuitHalfOpen:
+		if cb.probes > 0 {
+			cb.probes--
+		}
+	}
+
+	switch outcome {
+	case outcomeSuccess:
+		cb.state = CircuitClosed
+		cb.failures = 0
+	case outcomeFailure:
+		cb.failures++
+		if cb.state == CircuitHalfOpen || cb.failures >= b.cfg.FailureThreshold {
+			cb.state = CircuitOpen
+			cb.openedAt = time.Now()
+		}
+	}
+}
+
+func (b *circuitBreaker) state(baseURL, path string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cb := b.circuit(baseURL, path)
+	if cb.state == CircuitOpen && time.Since(cb.openedAt) >= b.cfg.Cooldown {
+		return CircuitHalfOpen
+	}
+	return cb.state
+}
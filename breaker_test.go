@@ -0,0 +1,164 @@
+package deepseek_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var healthy int32
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"boom"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(0),
+		deepseek.WithCircuitBreaker(deepseek.CircuitBreaker{
+			FailureThreshold: 2,
+			Cooldown:         50 * time.Millisecond,
+		}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, err = client.ListModels(context.Background())
+		require.Error(t, err)
+		assert.False(t, errors.Is(err, deepseek.ErrCircuitOpen))
+	}
+	assert.Equal(t, deepseek.CircuitOpen, client.CircuitState("/models"))
+	assert.Equal(t, deepseek.CircuitClosed, client.CircuitState("/user/balance"))
+
+	_, err = client.ListModels(context.Background())
+	require.ErrorIs(t, err, deepseek.ErrCircuitOpen)
+	var openErr *deepseek.CircuitOpenError
+	require.True(t, errors.As(err, &openErr))
+	assert.Equal(t, "/models", openErr.Path)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, deepseek.CircuitHalfOpen, client.CircuitState("/models"))
+
+	atomic.StoreInt32(&healthy, 1)
+	_, err = client.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, deepseek.CircuitClosed, client.CircuitState("/models"))
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"overloaded"}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(0),
+		deepseek.WithCircuitBreaker(deepseek.CircuitBreaker{
+			FailureThreshold: 1,
+			Cooldown:         20 * time.Millisecond,
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.GetBalance(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, deepseek.CircuitOpen, client.CircuitState("/user/balance"))
+
+	time.Sleep(30 * time.Millisecond)
+	_, err = client.GetBalance(context.Background())
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, deepseek.ErrCircuitOpen))
+	assert.Equal(t, deepseek.CircuitOpen, client.CircuitState("/user/balance"))
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"bad request"}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithCircuitBreaker(deepseek.CircuitBreaker{FailureThreshold: 1}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = client.ListModels(context.Background())
+		require.Error(t, err)
+	}
+	assert.Equal(t, deepseek.CircuitClosed, client.CircuitState("/models"))
+}
+
+func TestCircuitBreakerPerBaseURL(t *testing.T) {
+	var primaryHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/models" {
+			// Health checks pass, so the primary comes back into rotation
+			_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+			return
+		}
+		atomic.AddInt32(&primaryHits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"overloaded"}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"is_available":true}`))
+	}))
+	defer backup.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURLs(primary.URL, backup.URL),
+		deepseek.WithHealthCheckInterval(10*time.Millisecond),
+		deepseek.WithMaxRetries(0),
+		deepseek.WithCircuitBreaker(deepseek.CircuitBreaker{
+			FailureThreshold: 1,
+			Cooldown:         time.Minute,
+		}),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	// The primary's circuit opens without blocking the backup
+	balance, err := client.GetBalance(context.Background())
+	require.NoError(t, err)
+	assert.True(t, balance.IsAvailable)
+	assert.Equal(t, deepseek.CircuitOpen, client.CircuitState("/user/balance"))
+
+	require.Eventually(t, func() bool {
+		return client.EndpointStats()[0].Healthy
+	}, time.Second, 5*time.Millisecond)
+
+	// The open circuit moves the call on to the backup without reaching the primary
+	// or taking it out of rotation for its other paths
+	for i := 0; i < 2; i++ {
+		balance, err = client.GetBalance(context.Background())
+		require.NoError(t, err)
+		assert.True(t, balance.IsAvailable)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&primaryHits))
+	stats := client.EndpointStats()
+	assert.True(t, stats[0].Healthy)
+	assert.Equal(t, int64(1), stats[0].Failures)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/trustsight-io/deepseek-go/internal/errors"
@@ -20,7 +19,11 @@ const (
 	RoleUser Role = "user"
 	// RoleAssistant represents an assistant message in a chat conversation
 	RoleAssistant Role = "assistant"
-	// RoleFunction represents a function response message in a chat conversation
+	// RoleTool represents a tool result message in a chat conversation
+	RoleTool Role = "tool"
+	// RoleFunction represents a function response message in a chat conversation.
+	//
+	// Deprecated: use RoleTool; function messages are sent as tool messages.
 	RoleFunction Role = "function"
 )
 
@@ -30,6 +33,13 @@ type Message struct {
 	Content      string        `json:"content"`
 	Name         string        `json:"name,omitempty"`
 	FunctionCall *FunctionCall `json:"function_call,omitempty"`
+	// ReasoningContent holds the chain of thought of deepseek-reasoner. It is
+	// removed from messages before they are sent.
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// ToolCalls holds the tool calls requested by an assistant message
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	// ToolCallID is the ID of the tool call a tool message answers
+	ToolCallID string `json:"tool_call_id,omitempty"`
 }
 
 // FunctionCall represents a function call in a chat message
@@ -45,7 +55,8 @@ type Function struct {
 	Parameters  json.RawMessage `json:"parameters"`
 }
 
-// ChatCompletionRequest represents a request to the chat completions API
+// ChatCompletionRequest represents a request to the chat completions API.
+// Functions and FunctionCall are deprecated; they are sent as Tools and ToolChoice.
 type ChatCompletionRequest struct {
 	Model            string             `json:"model,omitempty"`
 	Messages         []Message          `json:"messages"`
@@ -64,9 +75,18 @@ type ChatCompletionRequest struct {
 	ResponseFormat   *struct {
 		Type string `json:"type"`
 	} `json:"response_format,omitempty"`
-	Seed     int64  `json:"seed,omitempty"`
-	Tools    []Tool `json:"tools,omitempty"`
-	JSONMode bool   `json:"json_mode,omitempty"`
+	Seed          int64          `json:"seed,omitempty"`
+	Tools         []Tool         `json:"tools,omitempty"`
+	ToolChoice    *ToolChoice    `json:"tool_choice,omitempty"`
+	JSONMode      bool           `json:"json_mode,omitempty"`
+	StreamOptions *StreamOptions `json:"stream_options,omitempty"`
+}
+
+// StreamOptions configures a streamed chat completion
+type StreamOptions struct {
+	// IncludeUsage requests a final chunk reporting the token usage of the
+	// stream, available from Stream.Usage once the stream ends
+	IncludeUsage bool `json:"include_usage"`
 }
 
 // Tool represents a tool that can be used by the model
@@ -98,12 +118,53 @@ type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
 	CompletionTokens int `json:"completion_tokens"`
 	TotalTokens      int `json:"total_tokens"`
+	// PromptCacheHitTokens is the number of prompt tokens served from the context cache
+	PromptCacheHitTokens int `json:"prompt_cache_hit_tokens"`
+	// PromptCacheMissTokens is the number of prompt tokens not found in the context cache
+	PromptCacheMissTokens int `json:"prompt_cache_miss_tokens"`
+}
+
+// add adds the tokens of other to u
+func (u *Usage) add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+	u.PromptCacheHitTokens += other.PromptCacheHitTokens
+	u.PromptCacheMissTokens += other.PromptCacheMissTokens
+}
+
+// chatModel returns the model of chat completions that do not specify one
+func (c *Client) chatModel() string {
+	if c.defaultModel != "" {
+		return c.defaultModel
+	}
+	return ModelChat
+}
+
+// outgoing returns the request as it is sent: translated from the functions
+// protocol, without reasoning content, and without stream options unless it
+// is streamed. The caller's request is not modified.
+func (r *ChatCompletionRequest) outgoing() *ChatCompletionRequest {
+	out := r.withTools()
+	copied := out != r
+	if hasReasoning(out.Messages) || (!out.Stream && out.StreamOptions != nil) {
+		if !copied {
+			cp := *r
+			out = &cp
+		}
+		out.Messages = StripReasoning(out.Messages)
+		if !out.Stream {
+			out.StreamOptions = nil
+		}
+	}
+	return out
 }
 
 // CreateChatCompletion sends a chat completion request to the API
 func (c *Client) CreateChatCompletion(
 	ctx context.Context,
 	req *ChatCompletionRequest,
+	opts ...RequestOption,
 ) (*ChatCompletionResponse, error) {
 	if req == nil {
 		return nil, &errors.InvalidRequestError{
@@ -119,57 +180,25 @@ func (c *Client) CreateChatCompletion(
 		}
 	}
 
-	if req.Model == "" {
-		req.Model = "deepseek-chat"
-	}
-
-	resp, err := c.createChatCompletion(ctx, req)
-	if err != nil {
+	if err := validateToolMessages(req.Messages); err != nil {
 		return nil, err
 	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			err = fmt.Errorf("error closing response body: %v", cerr)
-		}
-	}()
 
-	var response ChatCompletionResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
-	}
-
-	return &response, nil
-}
-
-// createChatCompletion handles the raw HTTP request to the chat completions API
-func (c *Client) createChatCompletion(
-	ctx context.Context,
-	req *ChatCompletionRequest,
-) (*http.Response, error) {
-	httpReq, err := c.newRequest(ctx, http.MethodPost, "/chat/completions", req)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+	if req.Model == "" {
+		req.Model = c.chatModel()
 	}
+	c.warnRequest(ctx, req)
 
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
+	var response ChatCompletionResponse
+	if err := c.do(ctx, newCall(http.MethodPost, "/chat/completions", req.outgoing(), opts), &response); err != nil {
 		return nil, err
 	}
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		if cerr := resp.Body.Close(); cerr != nil {
-			return nil, fmt.Errorf("error closing response body: %v", cerr)
-		}
-
-		var apiErr errors.APIError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return nil, fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(body))
-		}
-
-		apiErr.StatusCode = resp.StatusCode
-		return nil, &apiErr
+	if req.usesLegacyFunctions() {
+		setLegacyFunctionCalls(&response)
+	}
+	if c.parseThinkTags {
+		splitThinkTags(&response)
 	}
 
-	return resp, nil
+	return &response, nil
 }
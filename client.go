@@ -6,7 +6,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
+	"math/rand"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/trustsight-io/deepseek-go/internal/errors"
@@ -21,6 +25,7 @@ const (
 	defaultTimeout        = 30 * time.Second
 	defaultMaxRetries     = 3
 	defaultRetryWaitTime  = 1 * time.Second
+	defaultMaxRetryWait   = 30 * time.Second
 	defaultMaxRequestSize = 2 << 20 // 2MB
 )
 
@@ -31,13 +36,42 @@ type Client struct {
 	httpClient *http.Client
 
 	// Configuration options
-	maxRetries     int
-	retryWaitTime  time.Duration
-	maxRequestSize int64
+	maxRetries       int
+	retryWaitTime    time.Duration
+	maxRetryWaitTime time.Duration
+	maxRequestSize   int64
+	compressRequests bool
+	defaultModel     string
+	credentials      *credentialCache
+	authHeader       string
+	authScheme       string
+	parseThinkTags   bool
+
+	// optionErr is the first error reported by an option
+	optionErr error
 
 	// Feature flags
 	enableRetries bool
 	debug         bool
+
+	logger    *slog.Logger
+	redaction LogRedaction
+	tracer    Tracer
+	metrics   MetricsCollector
+
+	middleware  []Middleware
+	handler     Handler
+	limiter     *rateLimiter
+	concurrency *concurrencyLimiter
+	breaker     *circuitBreaker
+	hedging     *hedger
+	queue       *requestQueue
+	active      activeCalls
+	keys        *keyPool
+	endpoints   *endpointSet
+
+	healthCheckInterval time.Duration
+	stopHealthChecks    context.CancelFunc
 }
 
 // ClientOption represents a function that modifies the client configuration
@@ -47,6 +81,31 @@ type ClientOption func(*Client)
 func WithBaseURL(url string) ClientOption {
 	return func(c *Client) {
 		c.baseURL = url
+		c.endpoints = nil
+	}
+}
+
+// WithAPIKey sets the API key, overriding the one passed to NewClient
+func WithAPIKey(apiKey string) ClientOption {
+	return func(c *Client) {
+		c.apiKey = apiKey
+	}
+}
+
+// WithDefaultModel sets the model used by chat completions that do not specify one
+func WithDefaultModel(model string) ClientOption {
+	return func(c *Client) {
+		c.defaultModel = model
+	}
+}
+
+// WithTimeout sets the timeout of each HTTP request. The HTTP client is copied,
+// so a client passed to WithHTTPClient is not modified.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		hc := *c.httpClient
+		hc.Timeout = timeout
+		c.httpClient = &hc
 	}
 }
 
@@ -65,40 +124,56 @@ func WithMaxRetries(retries int) ClientOption {
 	}
 }
 
-// WithRetryWaitTime sets the wait time between retries
+// WithRetryWaitTime sets the base wait time between retries, which doubles with each attempt
 func WithRetryWaitTime(duration time.Duration) ClientOption {
 	return func(c *Client) {
 		c.retryWaitTime = duration
 	}
 }
 
-// WithMaxRequestSize sets the maximum request size in bytes
+// WithMaxRetryWaitTime caps the exponential backoff between retries
+func WithMaxRetryWaitTime(duration time.Duration) ClientOption {
+	return func(c *Client) {
+		c.maxRetryWaitTime = duration
+	}
+}
+
+// WithMaxRequestSize sets the maximum request size in bytes. Larger calls fail
+// with a *RequestTooLargeError before anything is sent; zero disables the check.
 func WithMaxRequestSize(size int64) ClientOption {
 	return func(c *Client) {
 		c.maxRequestSize = size
 	}
 }
 
-// WithDebug enables debug logging
+// WithDebug enables debug logging. Unless a logger is set with WithLogger,
+// debug output including full payloads is written to stderr.
 func WithDebug(debug bool) ClientOption {
 	return func(c *Client) {
 		c.debug = debug
 	}
 }
 
-// NewClient creates a new DeepSeek API client with the provided options
-func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
-	if apiKey == "" {
-		return nil, fmt.Errorf("API key cannot be empty")
+// errNoAPIKey is returned by NewClient when neither an API key nor a key pool is given
+var errNoAPIKey = fmt.Errorf("API key cannot be empty")
+
+// setOptionErr records an invalid option, which makes NewClient fail
+func (c *Client) setOptionErr(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
 	}
+}
 
+// NewClient creates a new DeepSeek API client with the provided options
+func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 	client := &Client{
-		baseURL:        defaultBaseURL,
-		apiKey:         apiKey,
-		maxRetries:     defaultMaxRetries,
-		retryWaitTime:  defaultRetryWaitTime,
-		maxRequestSize: defaultMaxRequestSize,
-		enableRetries:  true,
+		baseURL:          defaultBaseURL,
+		apiKey:           apiKey,
+		maxRetries:       defaultMaxRetries,
+		retryWaitTime:    defaultRetryWaitTime,
+		maxRetryWaitTime: defaultMaxRetryWait,
+		maxRequestSize:   defaultMaxRequestSize,
+		enableRetries:    true,
 		httpClient: &http.Client{
 			Timeout: defaultTimeout,
 		},
@@ -107,124 +182,562 @@ func NewClient(apiKey string, opts ...ClientOption) (*Client, error) {
 	for _, opt := range opts {
 		opt(client)
 	}
+	if client.optionErr != nil {
+		return nil, client.optionErr
+	}
 
-	return client, nil
-}
+	if client.keys != nil {
+		if len(client.keys.keys) == 0 {
+			return nil, fmt.Errorf("key pool cannot be empty")
+		}
+	} else if client.apiKey == "" && client.credentials == nil {
+		return nil, errNoAPIKey
+	}
 
-// Close closes any resources held by the client
-func (c *Client) Close() error {
-	// Currently no resources to clean up
-	return nil
+	if client.logger == nil && client.debug {
+		client.logger = defaultDebugLogger()
+	}
+
+	client.handler = chainMiddleware(client.roundTrip, client.middleware)
+
+	if client.endpoints != nil && len(client.endpoints.endpoints) > 1 {
+		client.startHealthChecks()
+	}
+
+	return client, nil
 }
 
-// newRequest creates a new HTTP request with the given method, path, and body
-func (c *Client) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+// encodeBody encodes the request payload of a call
+func encodeBody(r *Request) ([]byte, error) {
 	var buf bytes.Buffer
-	if body != nil {
-		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+	if r.Body != nil {
+		if err := json.NewEncoder(&buf).Encode(r.Body); err != nil {
 			return nil, fmt.Errorf("failed to encode request body: %v", err)
 		}
 	}
+	return buf.Bytes(), nil
+}
+
+// newRequest creates a new HTTP request for a single attempt of the given call
+func (c *Client) newRequest(ctx context.Context, r *Request, body *payload, state *callState) (*http.Request, error) {
+	if body == nil {
+		body = &payload{}
+	}
 
-	url := util.JoinURL(c.baseURL, path)
-	req, err := http.NewRequestWithContext(ctx, method, url, &buf)
+	url := util.JoinURL(state.baseURL, r.Path)
+	req, err := http.NewRequestWithContext(ctx, r.Method, url, bytes.NewReader(body.wire))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
+	if body.gzipped {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if c.compressRequests {
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	c.setAuth(req.Header, state.apiKey)
 	req.Header.Set("User-Agent", "deepseek-go/"+Version)
+	if c.tracer != nil {
+		c.tracer.Inject(ctx, req.Header)
+	}
+	for key, values := range r.Header {
+		req.Header[key] = values
+	}
 	return req, nil
 }
 
-// do executes an HTTP request with retries and error handling
-func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) error {
-	var lastErr error
+// do executes a call through the middleware chain and stores the decoded response in v
+func (c *Client) do(ctx context.Context, req *Request, v interface{}) error {
+	ctx, done, err := c.active.begin(ctx, false)
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	ctx, cancel := req.withTimeout(ctx)
+	defer cancel()
+
+	req.result = v
+	resp, err := c.handler(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return fmt.Errorf("middleware returned no response")
+	}
+	return assignResult(v, resp.Body)
+}
+
+// doStream executes a streaming call through the middleware chain
+func (c *Client) doStream(ctx context.Context, req *Request) (*Stream, error) {
+	ctx, done, err := c.active.begin(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := req.withTimeout(ctx)
+	release := func() {
+		cancel()
+		done()
+	}
+
+	req.Stream = true
+	resp, err := c.handler(ctx, req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	var stream *Stream
+	switch {
+	case resp == nil:
+		err = fmt.Errorf("middleware returned no response")
+	case resp.Stream != nil:
+		stream = resp.Stream
+	case resp.HTTPResponse != nil:
+		stream = c.openStream(ctx, req, resp.HTTPResponse, &callState{span: noopSpan{}})
+	default:
+		err = fmt.Errorf("middleware returned no stream")
+	}
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	// The stream stays tracked, and its timeout running, until it finishes or is closed
+	stream.release = release
+	return stream, nil
+}
+
+// roundTrip is the innermost Handler. It sends the call through the retry
+// pipeline, decodes the response and logs a summary of the call.
+func (c *Client) roundTrip(ctx context.Context, req *Request) (*Response, error) {
+	start := time.Now()
+	ctx, span := c.startSpan(ctx, req)
+	state := callState{span: span}
+	var resp *Response
+	body, err := c.encodePayload(req)
+	if err == nil {
+		if c.shouldHedge(req) {
+			resp, err = c.hedgedExchange(ctx, req, body, &state)
+		} else {
+			resp, err = c.exchange(ctx, req, body, &state)
+		}
+	}
+	elapsed := time.Since(start)
+	c.logCall(ctx, req, &state, resp, err, elapsed)
+	c.recordRequest(req, &state, resp, err, elapsed)
+	if req.options.meta != nil {
+		fillResponseMeta(req.options.meta, &state, elapsed)
+	}
+	if err != nil || !req.Stream {
+		// Streams end their span when they finish
+		endSpan(span, &state, resp, err)
+	}
+	return resp, err
+}
+
+// exchange sends a call with its encoded body and decodes its response
+func (c *Client) exchange(ctx context.Context, req *Request, body *payload, state *callState) (*Response, error) {
+	var reservation *rateReservation
+	if c.limiter != nil {
+		model, tokens := c.requestCost(req.Body)
+		var err error
+		if reservation, err = c.limiter.wait(ctx, model, tokens); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := c.send(ctx, req, body, state)
+	if err != nil {
+		// A request cancelled after it was sent, such as a hedging loser, may
+		// still be billed, so it keeps its estimate
+		if ctx.Err() == nil || state.attempts == 0 {
+			reservation.reconcile(0)
+		}
+		return nil, err
+	}
+
+	result := &Response{
+		StatusCode:   resp.StatusCode,
+		Header:       resp.Header,
+		HTTPResponse: resp,
+	}
+
+	if req.Stream {
+		result.Stream = c.openStream(ctx, req, resp, state)
+		result.Stream.reservation = reservation
+		return result, nil
+	}
+
+	respBody, err := readBody(resp)
+	if err != nil {
+		return nil, err
+	}
+	c.logResponse(ctx, resp, respBody)
+
+	if req.result != nil {
+		if err := json.Unmarshal(respBody, req.result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %v", err)
+		}
+		result.Body = req.result
+		if usage, ok := responseUsage(req.result); ok && usage.TotalTokens > 0 {
+			reservation.reconcile(usage.TotalTokens)
+			if c.keys != nil {
+				c.keys.recordUsage(state.apiKey, usage.TotalTokens)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// callState records what happened while sending a call
+type callState struct {
+	// attempts is the number of HTTP requests sent
+	attempts int
+	// statusCode is the status of the last response received
+	statusCode int
+	// header holds the headers of the last response received
+	header http.Header
+	// apiKey is the key used by the last attempt
+	apiKey string
+	// baseURL is the base URL used by the last attempt
+	baseURL string
+	// triedKeys holds the keys taken out of rotation during this call
+	triedKeys map[string]bool
+	// triedEndpoints holds the base URLs taken out of rotation during this call
+	triedEndpoints map[string]bool
+	// refreshedCredential is set once the credential was refreshed after a 401
+	refreshedCredential bool
+	// span traces the call
+	span Span
+}
 
-	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+// send executes a call with retries and error handling. It returns the first
+// successful response with its body unread; the caller must close it.
+// Error responses are converted into typed errors.
+func (c *Client) send(ctx context.Context, r *Request, body *payload, state *callState) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
 		if err := ctx.Err(); err != nil {
-			return err
+			return nil, err
+		}
+
+		// The first request was admitted by exchange with the call's token estimate
+		if state.attempts > 0 && c.limiter != nil {
+			if err := c.limiter.waitRequest(ctx, requestModel(r.Body)); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := c.selectKey(ctx, r, state); err != nil {
+			return nil, err
 		}
+		c.selectEndpoint(r, state)
 
-		resp, body, err := c.executeRequest(req)
+		state.attempts++
+		resp, err := c.attempt(ctx, r, body, state)
 		if err != nil {
-			lastErr = err
-			if !c.shouldRetryRequest(attempt, err) {
-				return err
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			if isCircuitOpen(err) && c.skipEndpoint(r, state) {
+				attempt--
+				continue
+			}
+			if !isAttemptError(err) {
+				return nil, err
+			}
+			if c.failoverEndpoint(r, state, 0, err) {
+				attempt--
+				continue
+			}
+			if !c.shouldRetryRequest(r, attempt, err) {
+				return nil, err
+			}
+			delay := c.backoff(r, attempt)
+			c.logRetry(ctx, r, state, delay, err)
+			addRetryEvent(state.span, state, delay, err)
+			c.recordRetry(r, err)
+			if werr := sleepContext(ctx, delay); werr != nil {
+				return nil, werr
 			}
-			time.Sleep(c.retryWaitTime * time.Duration(attempt+1))
 			continue
 		}
 
-		if err := c.handleResponse(resp, body, v); err != nil {
-			lastErr = err
-			if !c.shouldRetryResponse(attempt, resp.StatusCode) {
-				return err
-			}
-			time.Sleep(c.retryWaitTime * time.Duration(attempt+1))
+		state.statusCode = resp.StatusCode
+		state.header = resp.Header
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return resp, nil
+		}
+
+		respBody, err := readBody(resp)
+		if err != nil {
+			return nil, err
+		}
+		c.logResponse(ctx, resp, respBody)
+		err = c.handleErrorResponse(resp, respBody)
+		if c.failoverKey(r, state, resp.StatusCode, err) || c.failoverEndpoint(r, state, resp.StatusCode, err) ||
+			c.refreshCredential(r, state, resp.StatusCode) {
+			// The next key, endpoint or credential gets a fresh attempt without waiting
+			attempt--
 			continue
 		}
+		if !c.shouldRetryResponse(r, attempt, resp.StatusCode) {
+			return nil, err
+		}
+		delay := c.retryDelay(r, attempt, resp)
+		c.logRetry(ctx, r, state, delay, err)
+		addRetryEvent(state.span, state, delay, err)
+		c.recordRetry(r, err)
+		if werr := sleepContext(ctx, delay); werr != nil {
+			return nil, werr
+		}
+	}
+}
 
+// selectKey picks the API key for the next attempt of a call
+func (c *Client) selectKey(ctx context.Context, r *Request, state *callState) error {
+	if r.options.apiKey != "" {
+		state.apiKey = r.options.apiKey
+		return nil
+	}
+	if c.keys == nil && c.credentials != nil {
+		key, err := c.credentials.get(ctx)
+		if err != nil {
+			return err
+		}
+		state.apiKey = key
 		return nil
 	}
+	if c.keys == nil {
+		state.apiKey = c.apiKey
+		return nil
+	}
+
+	key, ok := c.keys.acquire(state.triedKeys)
+	if !ok {
+		return fmt.Errorf("no API key available in key pool")
+	}
+	state.apiKey = key
+	return nil
+}
+
+// failoverKey takes the key of a failed attempt out of rotation when the failure
+// is tied to the key. It reports whether another key is left to retry the call on.
+func (c *Client) failoverKey(r *Request, state *callState, statusCode int, err error) bool {
+	if c.keys == nil || r.options.apiKey != "" || !shouldFailover(statusCode) {
+		return false
+	}
+
+	c.keys.disable(state.apiKey, err)
+	if state.triedKeys == nil {
+		state.triedKeys = make(map[string]bool)
+	}
+	state.triedKeys[state.apiKey] = true
+	if len(state.triedKeys) < len(c.keys.keys) {
+		return true
+	}
+	// Every key failed; a retry starts over with the key that recovers first
+	state.triedKeys = nil
+	return false
+}
+
+// attemptError wraps a transport failure of a single attempt, which can be retried
+type attemptError struct {
+	err error
+}
+
+func (e *attemptError) Error() string {
+	return fmt.Sprintf("request failed: %v", e.err)
+}
+
+func (e *attemptError) Unwrap() error {
+	return e.err
+}
+
+// IsRetryable returns true; transport failures are usually transient
+func (e *attemptError) IsRetryable() bool {
+	return true
+}
 
-	return lastErr
+// isAttemptError reports whether err is a retryable transport failure
+func isAttemptError(err error) bool {
+	_, ok := err.(*attemptError)
+	return ok
 }
 
-// executeRequest executes a single HTTP request and returns the response and body
-func (c *Client) executeRequest(req *http.Request) (*http.Response, []byte, error) {
+// attempt sends a single HTTP request for the call. It is gated by the circuit
+// breaker and holds queue and concurrency slots until the response body has been
+// read or closed.
+func (c *Client) attempt(ctx context.Context, r *Request, body *payload, state *callState) (*http.Response, error) {
+	if c.breaker != nil {
+		if err := c.breaker.allow(state.baseURL, r.Path); err != nil {
+			return nil, err
+		}
+	}
+
+	req, err := c.newRequest(ctx, r, body, state)
+	if err != nil {
+		if c.breaker != nil {
+			c.breaker.record(state.baseURL, r.Path, outcomeNeutral)
+		}
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	c.logRequest(ctx, req, body.json)
+
+	releaseQueue := func() {}
+	if c.queue != nil {
+		release, err := c.queue.acquire(ctx)
+		if err != nil {
+			if c.breaker != nil {
+				c.breaker.record(state.baseURL, r.Path, outcomeNeutral)
+			}
+			return nil, err
+		}
+		releaseQueue = release
+	}
+
+	if c.concurrency != nil {
+		if err := c.concurrency.acquire(ctx); err != nil {
+			releaseQueue()
+			if c.breaker != nil {
+				c.breaker.record(state.baseURL, r.Path, outcomeNeutral)
+			}
+			return nil, err
+		}
+	}
+
+	// release frees the slots, recording the outcome of resp, which is nil when
+	// no response was received
+	release := func(resp *http.Response) {
+		if c.concurrency != nil {
+			c.concurrency.release(resp)
+		}
+		releaseQueue()
+	}
+
 	resp, err := c.httpClient.Do(req)
+	if c.breaker != nil {
+		c.breaker.record(state.baseURL, r.Path, classifyOutcome(ctx, resp, err))
+	}
 	if err != nil {
-		return nil, nil, fmt.Errorf("request failed: %v", err)
+		release(nil)
+		return nil, &attemptError{err: err}
+	}
+	if err := decompressResponse(resp); err != nil {
+		release(nil)
+		return nil, err
 	}
+	// The slots stay taken until the body has been read or closed
+	resp.Body = &slotBody{ReadCloser: resp.Body, release: func() { release(resp) }}
+	return resp, nil
+}
+
+// readBody reads and closes the response body
+func readBody(resp *http.Response) (body []byte, err error) {
 	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
+		if cerr := resp.Body.Close(); cerr != nil && err == nil {
 			err = fmt.Errorf("error closing response body: %v", cerr)
 		}
 	}()
 
-	body, err := io.ReadAll(resp.Body)
+	body, err = io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to read response body: %v", err)
+		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
+	return body, nil
+}
 
-	return resp, body, nil
+// handleErrorResponse converts a non-2xx response into a typed error. Bodies
+// that are not API errors, such as gateway error pages, are typed by status alone.
+func (c *Client) handleErrorResponse(resp *http.Response, body []byte) error {
+	apiErr, err := errors.ParseAPIError(resp.StatusCode, body)
+	switch {
+	case util.IsHTML(body):
+		apiErr = &errors.APIError{StatusCode: resp.StatusCode, Message: "received HTML response"}
+	case err != nil:
+		apiErr = &errors.APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return errors.HandleErrorResp(resp, apiErr)
 }
 
-// handleResponse processes the HTTP response and handles any errors
-func (c *Client) handleResponse(resp *http.Response, body []byte, v interface{}) error {
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		if util.IsHTML(body) {
-			return fmt.Errorf("received HTML response with status %d", resp.StatusCode)
-		}
+// sleepContext blocks for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
 
-		var apiErr errors.APIError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return fmt.Errorf("api error (status %d): %s", resp.StatusCode, string(body))
-		}
-		apiErr.StatusCode = resp.StatusCode
-		return errors.HandleErrorResp(resp, &apiErr)
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
 	}
+}
 
-	if v != nil {
-		if err := json.Unmarshal(body, v); err != nil {
-			return fmt.Errorf("failed to decode response: %v", err)
-		}
+// backoff returns the exponential backoff with jitter for the given attempt.
+// The delay doubles with every attempt, is capped at maxRetryWaitTime and is
+// randomized within [delay/2, delay) to avoid synchronized retries.
+func (c *Client) backoff(r *Request, attempt int) time.Duration {
+	delay := c.retryWaitTimeFor(r)
+	for i := 0; i < attempt && delay < c.maxRetryWaitTime; i++ {
+		delay *= 2
+	}
+	if delay > c.maxRetryWaitTime {
+		delay = c.maxRetryWaitTime
+	}
+	if delay <= 0 {
+		return 0
 	}
 
-	return nil
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1))
+}
+
+// retryDelay returns how long to wait before retrying after resp. A Retry-After
+// header sent by the server takes precedence over the computed backoff.
+func (c *Client) retryDelay(r *Request, attempt int, resp *http.Response) time.Duration {
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+		return d
+	}
+	return c.backoff(r, attempt)
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as an HTTP date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
 }
 
 // shouldRetryRequest determines if a request error should trigger a retry
-func (c *Client) shouldRetryRequest(attempt int, _ error) bool {
-	return c.enableRetries && attempt < c.maxRetries
+func (c *Client) shouldRetryRequest(r *Request, attempt int, _ error) bool {
+	return attempt < c.maxRetriesFor(r)
 }
 
 // shouldRetryResponse determines if a response should trigger a retry based on status code
-func (c *Client) shouldRetryResponse(attempt int, statusCode int) bool {
-	return shouldRetry(statusCode) && c.enableRetries && attempt < c.maxRetries
+func (c *Client) shouldRetryResponse(r *Request, attempt int, statusCode int) bool {
+	return shouldRetry(statusCode) && attempt < c.maxRetriesFor(r)
 }
 
 // shouldRetry returns true if the status code indicates a retryable error
@@ -2,7 +2,11 @@ package deepseek_test
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -179,3 +183,110 @@ func TestClientRetry(t *testing.T) {
 	// The error should indicate we tried multiple times
 	assert.Contains(t, respErr.Error(), "400")
 }
+
+func TestClientRetryReplaysBody(t *testing.T) {
+	var attempts int32
+	var bodies []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"overloaded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"chat-1","choices":[{"message":{"role":"assistant","content":"hi"}}]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(3),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "chat-1", resp.ID)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	require.Len(t, bodies, 3)
+	for _, body := range bodies {
+		assert.Equal(t, bodies[0], body)
+		assert.Contains(t, body, "Hello!")
+	}
+}
+
+func TestClientRetryHonorsRetryAfter(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"slow down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
+
+func TestClientRetryStopsOnContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"slow down"}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = client.GetBalance(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClientTypedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	assert.ErrorContains(t, err, "authentication failed")
+
+	_, err = client.CreateChatCompletionStream(context.Background(), req)
+	assert.ErrorContains(t, err, "authentication failed")
+}
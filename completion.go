@@ -44,6 +44,7 @@ type CompletionResponse struct {
 func (c *Client) CreateCompletion(
 	ctx context.Context,
 	request *CompletionRequest,
+	opts ...RequestOption,
 ) (*CompletionResponse, error) {
 	if request == nil {
 		return nil, &errors.InvalidRequestError{Param: "request", Err: fmt.Errorf("cannot be nil")}
@@ -57,10 +58,7 @@ func (c *Client) CreateCompletion(
 		request.Model = "deepseek-coder"
 	}
 
-	req, err := c.newRequest(ctx, http.MethodPost, "/completions", request)
-	if err != nil {
-		return nil, err
-	}
+	req := newCall(http.MethodPost, "/completions", request, opts)
 
 	var response CompletionResponse
 	if err := c.do(ctx, req, &response); err != nil {
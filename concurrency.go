@@ -0,0 +1,165 @@
+package deepseek
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+)
+
+const (
+	defaultConcurrencyInitialLimit = 4
+	defaultConcurrencyMinLimit     = 1
+	defaultConcurrencyMaxLimit     = 64
+	defaultConcurrencyBackoffRatio = 0.5
+)
+
+// AdaptiveConcurrency configures the AIMD concurrency limiter. Zero values
+// fall back to the defaults.
+type AdaptiveConcurrency struct {
+	// InitialLimit is the number of in-flight requests allowed at start (default 4)
+	InitialLimit int
+	// MinLimit is the lowest the limit can shrink to (default 1)
+	MinLimit int
+	// MaxLimit is the highest the limit can grow to (default 64)
+	MaxLimit int
+	// BackoffRatio is the factor the limit is multiplied by on overload (default 0.5)
+	BackoffRatio float64
+}
+
+// ConcurrencyStats describes the current state of the adaptive concurrency limiter
+type ConcurrencyStats struct {
+	// Limit is the number of in-flight requests currently allowed
+	Limit int
+	// InFlight is the number of requests whose response has not been read yet,
+	// open streams included
+	InFlight int
+	// Waiting is the number of requests waiting for a slot
+	Waiting int
+	// Increases counts the successful responses that grew the limit
+	Increases int64
+	// Decreases counts the overload responses that shrank the limit
+	Decreases int64
+}
+
+// WithAdaptiveConcurrency enables the adaptive concurrency limiter. The number of
+// in-flight requests grows by one per window of successful responses and is cut
+// multiplicatively when the API answers with 429 or 503.
+func WithAdaptiveConcurrency(cfg AdaptiveConcurrency) ClientOption {
+	return func(c *Client) {
+		c.concurrency = newConcurrencyLimiter(cfg)
+	}
+}
+
+// ConcurrencyStats returns the state of the adaptive concurrency limiter.
+// It returns the zero value when the limiter is not enabled.
+func (c *Client) ConcurrencyStats() ConcurrencyStats {
+	if c.concurrency == nil {
+		return ConcurrencyStats{}
+	}
+	return c.concurrency.stats()
+}
+
+// concurrencyLimiter is an AIMD limiter on the number of in-flight requests
+type concurrencyLimiter struct {
+	mu        sync.Mutex
+	cfg       AdaptiveConcurrency
+	limit     float64
+	inFlight  int
+	waiting   int
+	increases int64
+	decreases int64
+	// released is closed and replaced whenever a slot frees up or the limit grows
+	released chan struct{}
+}
+
+func newConcurrencyLimiter(cfg AdaptiveConcurrency) *concurrencyLimiter {
+	if cfg.MinLimit <= 0 {
+		cfg.MinLimit = defaultConcurrencyMinLimit
+	}
+	if cfg.MaxLimit <= 0 {
+		cfg.MaxLimit = defaultConcurrencyMaxLimit
+	}
+	if cfg.MaxLimit < cfg.MinLimit {
+		cfg.MaxLimit = cfg.MinLimit
+	}
+	if cfg.InitialLimit <= 0 {
+		cfg.InitialLimit = defaultConcurrencyInitialLimit
+	}
+	if cfg.BackoffRatio <= 0 || cfg.BackoffRatio >= 1 {
+		cfg.BackoffRatio = defaultConcurrencyBackoffRatio
+	}
+
+	initial := math.Max(float64(cfg.MinLimit), math.Min(float64(cfg.InitialLimit), float64(cfg.MaxLimit)))
+	return &concurrencyLimiter{
+		cfg:      cfg,
+		limit:    initial,
+		released: make(chan struct{}),
+	}
+}
+
+// acquire blocks until a slot is available or ctx is done
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	for l.inFlight >= int(l.limit) {
+		released := l.released
+		l.waiting++
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			l.mu.Lock()
+			l.waiting--
+			l.mu.Unlock()
+			return ctx.Err()
+		case <-released:
+		}
+
+		l.mu.Lock()
+		l.waiting--
+	}
+	l.inFlight++
+	l.mu.Unlock()
+	return nil
+}
+
+// release frees a slot once the response has been read and adjusts the limit based
+// on its status. A nil response means the request failed without a status and
+// leaves the limit unchanged.
+func (l *concurrencyLimiter) release(resp *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	switch {
+	case resp == nil:
+	case isOverloadStatus(resp.StatusCode):
+		l.limit = math.Max(float64(l.cfg.MinLimit), l.limit*l.cfg.BackoffRatio)
+		l.decreases++
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if l.limit < float64(l.cfg.MaxLimit) {
+			l.limit = math.Min(float64(l.cfg.MaxLimit), l.limit+1/l.limit)
+			l.increases++
+		}
+	}
+
+	close(l.released)
+	l.released = make(chan struct{})
+}
+
+func (l *concurrencyLimiter) stats() ConcurrencyStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ConcurrencyStats{
+		Limit:     int(l.limit),
+		InFlight:  l.inFlight,
+		Waiting:   l.waiting,
+		Increases: l.increases,
+		Decreases: l.decreases,
+	}
+}
+
+// isOverloadStatus reports whether the status code signals that the API is overloaded
+func isOverloadStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
@@ -0,0 +1,148 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestAdaptiveConcurrencyBacksOffAndRecovers(t *testing.T) {
+	var overloaded int32 = 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&overloaded) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"message":"slow down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(0),
+		deepseek.WithAdaptiveConcurrency(deepseek.AdaptiveConcurrency{InitialLimit: 8, MaxLimit: 16}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 8, client.ConcurrencyStats().Limit)
+
+	_, err = client.ListModels(context.Background())
+	require.Error(t, err)
+	_, err = client.ListModels(context.Background())
+	require.Error(t, err)
+
+	stats := client.ConcurrencyStats()
+	assert.Equal(t, 2, stats.Limit)
+	assert.Equal(t, int64(2), stats.Decreases)
+	assert.Equal(t, 0, stats.InFlight)
+
+	atomic.StoreInt32(&overloaded, 0)
+	for i := 0; i < 10; i++ {
+		_, err = client.ListModels(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Greater(t, client.ConcurrencyStats().Limit, 2)
+}
+
+func TestAdaptiveConcurrencyLimitsInFlight(t *testing.T) {
+	var current, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithAdaptiveConcurrency(deepseek.AdaptiveConcurrency{InitialLimit: 2, MaxLimit: 2}),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.ListModels(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+}
+
+func TestAdaptiveConcurrencyRespectsContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithAdaptiveConcurrency(deepseek.AdaptiveConcurrency{InitialLimit: 1, MaxLimit: 1}),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_, _ = client.ListModels(context.Background())
+	}()
+	require.Eventually(t, func() bool { return client.ConcurrencyStats().InFlight == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.ListModels(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, client.ConcurrencyStats().Waiting)
+}
+
+func TestAdaptiveConcurrencyHoldsSlotForStreams(t *testing.T) {
+	server := sseServer(t, `{"choices":[{"index":0,"delta":{"content":"hi"},"finish_reason":"stop"}]}`)
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithAdaptiveConcurrency(deepseek.AdaptiveConcurrency{InitialLimit: 1, MaxLimit: 1}),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	}
+	stream, err := client.CreateChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.ConcurrencyStats().InFlight)
+
+	// The open stream keeps its slot
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateChatCompletionStream(ctx, req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	content, err := deepseek.CollectFullResponse(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "hi", content)
+	assert.Equal(t, 0, client.ConcurrencyStats().InFlight)
+
+	stream, err = client.CreateChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+	assert.Equal(t, 0, client.ConcurrencyStats().InFlight)
+}
@@ -0,0 +1,279 @@
+package deepseek
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environment variables read by NewClientFromEnv
+const (
+	EnvAPIKey     = "DEEPSEEK_API_KEY"
+	EnvBaseURL    = "DEEPSEEK_BASE_URL"
+	EnvTimeout    = "DEEPSEEK_TIMEOUT"
+	EnvMaxRetries = "DEEPSEEK_MAX_RETRIES"
+	EnvProxy      = "DEEPSEEK_PROXY"
+	EnvModel      = "DEEPSEEK_MODEL"
+	EnvConfig     = "DEEPSEEK_CONFIG"
+	EnvProfile    = "DEEPSEEK_PROFILE"
+)
+
+// Profile holds the settings of a client. Durations use Go syntax such as "30s".
+type Profile struct {
+	// APIKey is the API key
+	APIKey string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
+	// APIKeyEnv names an environment variable holding the API key, keeping it out of the file
+	APIKeyEnv string `json:"api_key_env,omitempty" yaml:"api_key_env,omitempty"`
+	// BaseURL is the base URL of the API
+	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
+	// Timeout is the timeout of each HTTP request
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// MaxRetries is the maximum number of retries for failed requests
+	MaxRetries *int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+	// RetryWaitTime is the base wait time between retries
+	RetryWaitTime string `json:"retry_wait_time,omitempty" yaml:"retry_wait_time,omitempty"`
+	// Proxy is the URL of an HTTP, HTTPS or SOCKS5 proxy
+	Proxy string `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	// CABundle is a PEM file of CA certificates to trust in addition to the system roots
+	CABundle string `json:"ca_bundle,omitempty" yaml:"ca_bundle,omitempty"`
+	// ClientCert and ClientKey are PEM files of a client certificate for mutual TLS
+	ClientCert string `json:"client_cert,omitempty" yaml:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty" yaml:"client_key,omitempty"`
+	// DefaultModel is the model of chat completions that do not specify one
+	DefaultModel string `json:"default_model,omitempty" yaml:"default_model,omitempty"`
+}
+
+// Config is a set of named profiles, such as dev, prod and local-mock
+type Config struct {
+	// DefaultProfile is used when no profile is named
+	DefaultProfile string `json:"default_profile,omitempty" yaml:"default_profile,omitempty"`
+	// Profiles maps profile names to their settings
+	Profiles map[string]Profile `json:"profiles" yaml:"profiles"`
+}
+
+// LoadConfig reads a JSON or YAML config file, chosen by its extension.
+// Unknown fields are rejected so that typos do not go unnoticed.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %v", err)
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q: use .json, .yaml or .yml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %v", path, err)
+	}
+	if len(cfg.Profiles) == 0 {
+		return nil, fmt.Errorf("config %s defines no profiles", path)
+	}
+	return &cfg, nil
+}
+
+// Profile returns the named profile. An empty name selects DefaultProfile, or
+// the only profile when there is just one.
+func (c *Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = c.DefaultProfile
+	}
+	if name == "" && len(c.Profiles) == 1 {
+		for _, p := range c.Profiles {
+			return p, nil
+		}
+	}
+	if name == "" {
+		return Profile{}, fmt.Errorf("no profile selected, available profiles: %s", c.profileNames())
+	}
+
+	p, ok := c.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("profile %q not found, available profiles: %s", name, c.profileNames())
+	}
+	return p, nil
+}
+
+func (c *Config) profileNames() string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// Key returns the API key of the profile, read from APIKeyEnv if APIKey is empty
+func (p Profile) Key() string {
+	if p.APIKey == "" && p.APIKeyEnv != "" {
+		return os.Getenv(p.APIKeyEnv)
+	}
+	return p.APIKey
+}
+
+// Options validates the profile and converts it into client options. The API
+// key is not included; pass Key to NewClient.
+func (p Profile) Options() ([]ClientOption, error) {
+	var opts []ClientOption
+
+	if p.BaseURL != "" {
+		u, err := url.Parse(p.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid base_url %q: must be an absolute http or https URL", p.BaseURL)
+		}
+		opts = append(opts, WithBaseURL(p.BaseURL))
+	}
+	if p.Timeout != "" {
+		d, err := parsePositiveDuration(p.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout: %v", err)
+		}
+		opts = append(opts, WithTimeout(d))
+	}
+	if p.MaxRetries != nil {
+		if *p.MaxRetries < 0 {
+			return nil, fmt.Errorf("invalid max_retries %d: cannot be negative", *p.MaxRetries)
+		}
+		opts = append(opts, WithMaxRetries(*p.MaxRetries))
+	}
+	if p.RetryWaitTime != "" {
+		d, err := parsePositiveDuration(p.RetryWaitTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid retry_wait_time: %v", err)
+		}
+		opts = append(opts, WithRetryWaitTime(d))
+	}
+	if p.Proxy != "" {
+		if _, err := parseProxyURL(p.Proxy); err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithProxy(p.Proxy))
+	}
+	if p.CABundle != "" {
+		opts = append(opts, WithCABundle(p.CABundle))
+	}
+	if (p.ClientCert == "") != (p.ClientKey == "") {
+		return nil, fmt.Errorf("client_cert and client_key must be set together")
+	}
+	if p.ClientCert != "" {
+		opts = append(opts, WithClientCertificate(p.ClientCert, p.ClientKey))
+	}
+	if p.DefaultModel != "" {
+		opts = append(opts, WithDefaultModel(p.DefaultModel))
+	}
+	return opts, nil
+}
+
+func parsePositiveDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%q must be positive", s)
+	}
+	return d, nil
+}
+
+// NewClientFromConfig creates a client from a profile of a JSON or YAML config
+// file. An empty profile name selects the default profile. Options passed
+// explicitly override the values of the file.
+func NewClientFromConfig(path, profile string, opts ...ClientOption) (*Client, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := cfg.Profile(profile)
+	if err != nil {
+		return nil, err
+	}
+	if profile == "" {
+		profile = cfg.DefaultProfile
+	}
+	return newClientFromProfile(p, profile, opts)
+}
+
+// NewClientFromEnv creates a client from environment variables. If
+// DEEPSEEK_CONFIG names a config file, the profile named by DEEPSEEK_PROFILE is
+// loaded first and the other variables override its values:
+//
+//	DEEPSEEK_API_KEY      API key
+//	DEEPSEEK_BASE_URL     base URL of the API
+//	DEEPSEEK_TIMEOUT      timeout of each HTTP request, e.g. "30s"
+//	DEEPSEEK_MAX_RETRIES  maximum number of retries
+//	DEEPSEEK_PROXY        HTTP, HTTPS or SOCKS5 proxy URL
+//	DEEPSEEK_MODEL        default chat model
+//
+// Options passed explicitly override both.
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	var p Profile
+	name := os.Getenv(EnvProfile)
+	if path := os.Getenv(EnvConfig); path != "" {
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		if p, err = cfg.Profile(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if v := os.Getenv(EnvAPIKey); v != "" {
+		p.APIKey = v
+	}
+	if v := os.Getenv(EnvBaseURL); v != "" {
+		p.BaseURL = v
+	}
+	if v := os.Getenv(EnvTimeout); v != "" {
+		p.Timeout = v
+	}
+	if v := os.Getenv(EnvMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: must be an integer", EnvMaxRetries, v)
+		}
+		p.MaxRetries = &n
+	}
+	if v := os.Getenv(EnvProxy); v != "" {
+		p.Proxy = v
+	}
+	if v := os.Getenv(EnvModel); v != "" {
+		p.DefaultModel = v
+	}
+
+	client, err := newClientFromProfile(p, name, opts)
+	if err == errNoAPIKey {
+		return nil, fmt.Errorf("%v: set %s", err, EnvAPIKey)
+	}
+	return client, err
+}
+
+// newClientFromProfile creates a client from p, with opts applied after the profile
+func newClientFromProfile(p Profile, name string, opts []ClientOption) (*Client, error) {
+	profileOpts, err := p.Options()
+	if err != nil {
+		if name != "" {
+			return nil, fmt.Errorf("profile %q: %v", name, err)
+		}
+		return nil, err
+	}
+	return NewClient(p.Key(), append(profileOpts, opts...)...)
+}
@@ -0,0 +1,152 @@
+package deepseek_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+// modelServer records the Authorization header and model of chat requests
+func modelServer(t *testing.T, auth, model *string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*auth = r.Header.Get("Authorization")
+		var req deepseek.ChatCompletionRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		*model = req.Model
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func sendChat(t *testing.T, client *deepseek.Client) {
+	_, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+}
+
+func TestNewClientFromConfigYAML(t *testing.T) {
+	var auth, model string
+	server := modelServer(t, &auth, &model)
+
+	path := writeFile(t, "deepseek.yaml", `
+default_profile: prod
+profiles:
+  prod:
+    api_key: prod-key
+    base_url: https://api.deepseek.com
+  local-mock:
+    api_key_env: MOCK_KEY
+    base_url: `+server.URL+`
+    timeout: 5s
+    max_retries: 0
+    default_model: deepseek-reasoner
+`)
+	t.Setenv("MOCK_KEY", "mock-key")
+
+	client, err := deepseek.NewClientFromConfig(path, "local-mock")
+	require.NoError(t, err)
+	sendChat(t, client)
+	assert.Equal(t, "Bearer mock-key", auth)
+	assert.Equal(t, "deepseek-reasoner", model)
+
+	// Explicit options override the file
+	client, err = deepseek.NewClientFromConfig(path, "local-mock",
+		deepseek.WithAPIKey("explicit-key"),
+		deepseek.WithDefaultModel("deepseek-chat"),
+	)
+	require.NoError(t, err)
+	sendChat(t, client)
+	assert.Equal(t, "Bearer explicit-key", auth)
+	assert.Equal(t, "deepseek-chat", model)
+}
+
+func TestNewClientFromConfigJSON(t *testing.T) {
+	path := writeFile(t, "deepseek.json", `{"profiles": {"dev": {"api_key": "dev-key", "proxy": "socks5://localhost:1080"}}}`)
+
+	// A single profile is selected without naming it
+	client, err := deepseek.NewClientFromConfig(path, "")
+	require.NoError(t, err)
+	assert.NotNil(t, client)
+}
+
+func TestConfigValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		profile string
+		wantErr string
+	}{
+		{"unknown field", "c.yaml", "profiles:\n  dev:\n    api_kee: x\n", "dev", "field api_kee not found"},
+		{"unknown profile", "c.json", `{"profiles":{"dev":{"api_key":"x"},"prod":{"api_key":"y"}}}`, "staging", `profile "staging" not found, available profiles: dev, prod`},
+		{"no profile selected", "c.json", `{"profiles":{"dev":{"api_key":"x"},"prod":{"api_key":"y"}}}`, "", "no profile selected"},
+		{"bad timeout", "c.json", `{"profiles":{"dev":{"api_key":"x","timeout":"soon"}}}`, "dev", `profile "dev": invalid timeout`},
+		{"negative retries", "c.json", `{"profiles":{"dev":{"api_key":"x","max_retries":-1}}}`, "dev", "invalid max_retries -1"},
+		{"bad base url", "c.json", `{"profiles":{"dev":{"api_key":"x","base_url":"api.deepseek.com"}}}`, "dev", "invalid base_url"},
+		{"bad proxy", "c.json", `{"profiles":{"dev":{"api_key":"x","proxy":"ftp://proxy"}}}`, "dev", "invalid proxy URL"},
+		{"unsupported format", "c.toml", `x = 1`, "", "unsupported config format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, tt.file, tt.content)
+			_, err := deepseek.NewClientFromConfig(path, tt.profile)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	var auth, model string
+	server := modelServer(t, &auth, &model)
+
+	path := writeFile(t, "deepseek.yaml", `
+profiles:
+  dev:
+    api_key: file-key
+    base_url: https://unused.example.com
+    default_model: deepseek-chat
+`)
+	t.Setenv(deepseek.EnvConfig, path)
+	t.Setenv(deepseek.EnvProfile, "dev")
+	t.Setenv(deepseek.EnvBaseURL, server.URL)
+	t.Setenv(deepseek.EnvModel, "deepseek-reasoner")
+	t.Setenv(deepseek.EnvMaxRetries, "1")
+
+	client, err := deepseek.NewClientFromEnv()
+	require.NoError(t, err)
+	sendChat(t, client)
+	assert.Equal(t, "Bearer file-key", auth)
+	assert.Equal(t, "deepseek-reasoner", model)
+}
+
+func TestNewClientFromEnvErrors(t *testing.T) {
+	t.Setenv(deepseek.EnvConfig, "")
+	t.Setenv(deepseek.EnvAPIKey, "")
+	_, err := deepseek.NewClientFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), deepseek.EnvAPIKey)
+
+	t.Setenv(deepseek.EnvAPIKey, "key")
+	t.Setenv(deepseek.EnvMaxRetries, "many")
+	_, err = deepseek.NewClientFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), deepseek.EnvMaxRetries)
+}
@@ -0,0 +1,147 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultAuthHeader = "Authorization"
+	defaultAuthScheme = "Bearer"
+)
+
+// CredentialProvider supplies the API key sent with each request, for keys
+// that live outside the process or rotate while it runs
+type CredentialProvider interface {
+	// Credential returns the current API key
+	Credential(ctx context.Context) (string, error)
+}
+
+// CredentialFunc adapts a function to a CredentialProvider
+type CredentialFunc func(ctx context.Context) (string, error)
+
+// Credential calls f
+func (f CredentialFunc) Credential(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// FileCredential reads the API key from a file, such as a mounted secret.
+// Surrounding whitespace is trimmed.
+func FileCredential(path string) CredentialProvider {
+	return CredentialFunc(func(context.Context) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	})
+}
+
+// CommandCredential runs a command and uses its output as the API key, e.g. a
+// secret manager CLI. Surrounding whitespace is trimmed.
+func CommandCredential(name string, args ...string) CredentialProvider {
+	return CredentialFunc(func(ctx context.Context) (string, error) {
+		out, err := exec.CommandContext(ctx, name, args...).Output()
+		if err != nil {
+			return "", fmt.Errorf("%s: %v", name, err)
+		}
+		return strings.TrimSpace(string(out)), nil
+	})
+}
+
+// WithCredentialProvider takes the API key of each request from provider
+// instead of a static key. Keys are cached for ttl, or until the API rejects
+// them when ttl is zero. A request rejected with an AuthenticationError is
+// retried once with a freshly fetched key.
+func WithCredentialProvider(provider CredentialProvider, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.credentials = &credentialCache{provider: provider, ttl: ttl}
+	}
+}
+
+// WithAuthHeader sends the API key in the given header with the given scheme,
+// for gateways that do not expect "Authorization: Bearer <key>". An empty
+// scheme sends the bare key, e.g. WithAuthHeader("X-API-Key", "").
+func WithAuthHeader(header, scheme string) ClientOption {
+	return func(c *Client) {
+		c.authHeader = header
+		c.authScheme = scheme
+	}
+}
+
+// authHeaderName returns the header that carries the API key
+func (c *Client) authHeaderName() string {
+	if c.authHeader == "" {
+		return defaultAuthHeader
+	}
+	return c.authHeader
+}
+
+// setAuth sets the API key on an outgoing request
+func (c *Client) setAuth(header http.Header, apiKey string) {
+	value := apiKey
+	if c.authHeader == "" {
+		value = defaultAuthScheme + " " + apiKey
+	} else if c.authScheme != "" {
+		value = c.authScheme + " " + apiKey
+	}
+	header.Set(c.authHeaderName(), value)
+}
+
+// refreshCredential drops a cached key the API rejected. It reports whether
+// the call should be retried with a fresh key, which happens once per call.
+func (c *Client) refreshCredential(r *Request, state *callState, statusCode int) bool {
+	if c.credentials == nil || c.keys != nil || r.options.apiKey != "" ||
+		statusCode != http.StatusUnauthorized || state.refreshedCredential {
+		return false
+	}
+	state.refreshedCredential = true
+	c.credentials.invalidate(state.apiKey)
+	return true
+}
+
+// credentialCache caches the key of a CredentialProvider
+type credentialCache struct {
+	mu       sync.Mutex
+	provider CredentialProvider
+	ttl      time.Duration
+	key      string
+	fetched  time.Time
+}
+
+// get returns the cached key, fetching a new one when there is none or it expired
+func (cc *credentialCache) get(ctx context.Context) (string, error) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.key != "" && (cc.ttl <= 0 || time.Since(cc.fetched) < cc.ttl) {
+		return cc.key, nil
+	}
+
+	key, err := cc.provider.Credential(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get credential: %w", err)
+	}
+	if key == "" {
+		return "", fmt.Errorf("failed to get credential: provider returned an empty key")
+	}
+	cc.key = key
+	cc.fetched = time.Now()
+	return key, nil
+}
+
+// invalidate drops key from the cache, unless it was already replaced
+func (cc *credentialCache) invalidate(key string) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.key == key {
+		cc.key = ""
+	}
+}
@@ -0,0 +1,194 @@
+package deepseek_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+// authServer accepts requests whose Authorization header is "Bearer <valid key>"
+// and records the header of each request
+func authServer(t *testing.T, valid *atomic.Value, seen *[]string) *httptest.Server {
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		mu.Lock()
+		*seen = append(*seen, auth)
+		mu.Unlock()
+		if auth != "Bearer "+valid.Load().(string) {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":{"message":"invalid api key","type":"authentication_error"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCredentialProviderCached(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("key-1")
+	var seen []string
+	server := authServer(t, &valid, &seen)
+
+	var calls int32
+	provider := deepseek.CredentialFunc(func(context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "key-1", nil
+	})
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithCredentialProvider(provider, time.Hour),
+	)
+	require.NoError(t, err)
+
+	sendChat(t, client)
+	sendChat(t, client)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, []string{"Bearer key-1", "Bearer key-1"}, seen)
+}
+
+func TestCredentialProviderTTL(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("key")
+	var seen []string
+	server := authServer(t, &valid, &seen)
+
+	var calls int32
+	provider := deepseek.CredentialFunc(func(context.Context) (string, error) {
+		atomic.AddInt32(&calls, 1)
+		return "key", nil
+	})
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithCredentialProvider(provider, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	sendChat(t, client)
+	time.Sleep(5 * time.Millisecond)
+	sendChat(t, client)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCredentialProviderRefreshOn401(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("key-1")
+	var seen []string
+	server := authServer(t, &valid, &seen)
+
+	var current atomic.Value
+	current.Store("key-1")
+	provider := deepseek.CredentialFunc(func(context.Context) (string, error) {
+		return current.Load().(string), nil
+	})
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(0),
+		deepseek.WithCredentialProvider(provider, 0),
+	)
+	require.NoError(t, err)
+	sendChat(t, client)
+
+	// The key is rotated; the cached key is rejected once and then replaced
+	valid.Store("key-2")
+	current.Store("key-2")
+	sendChat(t, client)
+	assert.Equal(t, []string{"Bearer key-1", "Bearer key-1", "Bearer key-2"}, seen)
+
+	// A key that stays invalid is not retried more than once
+	valid.Store("key-3")
+	seen = nil
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	assert.ErrorIs(t, err, deepseek.ErrAuthentication)
+	assert.Len(t, seen, 2)
+}
+
+func TestCredentialProviderError(t *testing.T) {
+	var valid atomic.Value
+	valid.Store("key")
+	var seen []string
+	server := authServer(t, &valid, &seen)
+
+	providerErr := errors.New("vault unavailable")
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithCredentialProvider(deepseek.CredentialFunc(func(context.Context) (string, error) {
+			return "", providerErr
+		}), 0),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	assert.ErrorIs(t, err, providerErr)
+	assert.Empty(t, seen)
+}
+
+func TestFileCredential(t *testing.T) {
+	path := writeFile(t, "key", "file-key\n")
+	key, err := deepseek.FileCredential(path).Credential(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "file-key", key)
+
+	_, err = deepseek.FileCredential(path + ".missing").Credential(context.Background())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestCommandCredential(t *testing.T) {
+	key, err := deepseek.CommandCredential("echo", "command-key").Credential(context.Background())
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skip("echo is not available")
+	}
+	require.NoError(t, err)
+	assert.Equal(t, "command-key", key)
+
+	_, err = deepseek.CommandCredential("false").Credential(context.Background())
+	assert.Error(t, err)
+}
+
+func TestAuthHeader(t *testing.T) {
+	var auth, apiKey string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		apiKey = r.Header.Get("X-API-Key")
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	client, err := deepseek.NewClient("sk-secret-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithAuthHeader("X-API-Key", ""),
+		deepseek.WithLogger(logger),
+	)
+	require.NoError(t, err)
+	sendChat(t, client)
+
+	assert.Empty(t, auth)
+	assert.Equal(t, "sk-secret-key", apiKey)
+	assert.NotContains(t, buf.String(), "sk-secret-key")
+
+	client, err = deepseek.NewClient("sk-secret-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithAuthHeader("Authorization", "Token"),
+	)
+	require.NoError(t, err)
+	sendChat(t, client)
+	assert.Equal(t, "Token sk-secret-key", auth)
+}
@@ -0,0 +1,274 @@
+package deepseek
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultHealthCheckInterval = 30 * time.Second
+
+// EndpointStats describes the health of a base URL
+type EndpointStats struct {
+	// URL is the base URL
+	URL string
+	// Healthy reports whether the endpoint is receiving traffic
+	Healthy bool
+	// Failures is the number of failures that took the endpoint out of rotation
+	Failures int64
+	// LastError is the last error that took the endpoint out of rotation
+	LastError string
+	// LastChecked is when the endpoint was last probed by the health checker
+	LastChecked time.Time
+}
+
+// WithBaseURLs sets an ordered list of base URLs. Calls go to the first healthy
+// URL; connection errors and 5xx responses fail over to the next one. Unhealthy
+// URLs are probed in the background with a models listing and brought back
+// once they respond.
+func WithBaseURLs(urls ...string) ClientOption {
+	return func(c *Client) {
+		if len(urls) == 0 {
+			return
+		}
+		c.baseURL = urls[0]
+		c.endpoints = newEndpointSet(urls)
+	}
+}
+
+// WithHealthCheckInterval sets how often unhealthy base URLs are probed (default 30s)
+func WithHealthCheckInterval(interval time.Duration) ClientOption {
+	return func(c *Client) {
+		c.healthCheckInterval = interval
+	}
+}
+
+// EndpointStats returns the health of every base URL set with WithBaseURLs.
+// It returns nil when a single base URL is used.
+func (c *Client) EndpointStats() []EndpointStats {
+	if c.endpoints == nil {
+		return nil
+	}
+	return c.endpoints.stats()
+}
+
+// endpointSet tracks the health of an ordered list of base URLs
+type endpointSet struct {
+	mu        sync.Mutex
+	endpoints []*endpoint
+}
+
+// endpoint is a base URL and its health
+type endpoint struct {
+	url         string
+	healthy     bool
+	failures    int64
+	failedAt    time.Time
+	lastError   string
+	lastChecked time.Time
+}
+
+func newEndpointSet(urls []string) *endpointSet {
+	s := &endpointSet{}
+	for _, url := range urls {
+		s.endpoints = append(s.endpoints, &endpoint{url: url, healthy: true})
+	}
+	return s
+}
+
+// acquire returns the first healthy URL not in exclude. When every remaining URL is
+// unhealthy, the one that failed longest ago is used. It returns false when every
+// URL has been excluded.
+func (s *endpointSet) acquire(exclude map[string]bool) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var fallback *endpoint
+	for _, e := range s.endpoints {
+		if exclude[e.url] {
+			continue
+		}
+		if e.healthy {
+			return e.url, true
+		}
+		if fallback == nil || e.failedAt.Before(fallback.failedAt) {
+			fallback = e
+		}
+	}
+
+	if fallback == nil {
+		return "", false
+	}
+	return fallback.url, true
+}
+
+// markUnhealthy takes a URL out of rotation
+func (s *endpointSet) markUnhealthy(url string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.endpoints {
+		if e.url == url {
+			e.healthy = false
+			e.failures++
+			e.failedAt = time.Now()
+			if err != nil {
+				e.lastError = err.Error()
+			}
+			return
+		}
+	}
+}
+
+// unhealthy returns the URLs currently out of rotation
+func (s *endpointSet) unhealthy() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var urls []string
+	for _, e := range s.endpoints {
+		if !e.healthy {
+			urls = append(urls, e.url)
+		}
+	}
+	return urls
+}
+
+// recordCheck stores the result of a health probe
+func (s *endpointSet) recordCheck(url string, healthy bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.endpoints {
+		if e.url == url {
+			e.lastChecked = time.Now()
+			if healthy {
+				e.healthy = true
+			}
+			return
+		}
+	}
+}
+
+func (s *endpointSet) stats() []EndpointStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]EndpointStats, 0, len(s.endpoints))
+	for _, e := range s.endpoints {
+		stats = append(stats, EndpointStats{
+			URL:         e.url,
+			Healthy:     e.healthy,
+			Failures:    e.failures,
+			LastError:   e.lastError,
+			LastChecked: e.lastChecked,
+		})
+	}
+	return stats
+}
+
+// selectEndpoint picks the base URL for the next attempt of a call
+func (c *Client) selectEndpoint(r *Request, state *callState) {
+	if r.options.baseURL != "" {
+		state.baseURL = r.options.baseURL
+		return
+	}
+	state.baseURL = c.baseURL
+	if c.endpoints == nil {
+		return
+	}
+	if url, ok := c.endpoints.acquire(state.triedEndpoints); ok {
+		state.baseURL = url
+	}
+}
+
+// failoverEndpoint takes the base URL of a failed attempt out of rotation when the
+// failure points at the endpoint, that is a connection error (statusCode 0) or a
+// 5xx response. It reports whether another URL is left to retry the call on.
+func (c *Client) failoverEndpoint(r *Request, state *callState, statusCode int, err error) bool {
+	if c.endpoints == nil || r.options.baseURL != "" || (statusCode != 0 && statusCode < http.StatusInternalServerError) {
+		return false
+	}
+
+	c.endpoints.markUnhealthy(state.baseURL, err)
+	return c.nextEndpoint(state)
+}
+
+// skipEndpoint moves a call rejected by an open circuit on to the next base URL.
+// The health of the URL is left alone, as its other paths may still work.
+func (c *Client) skipEndpoint(r *Request, state *callState) bool {
+	if c.endpoints == nil || r.options.baseURL != "" {
+		return false
+	}
+	return c.nextEndpoint(state)
+}
+
+// nextEndpoint records the base URL of the last attempt as tried and reports
+// whether another URL is left to try
+func (c *Client) nextEndpoint(state *callState) bool {
+	if state.triedEndpoints == nil {
+		state.triedEndpoints = make(map[string]bool)
+	}
+	state.triedEndpoints[state.baseURL] = true
+	if len(state.triedEndpoints) < len(c.endpoints.endpoints) {
+		return true
+	}
+	// Every URL failed; a retry starts over with the healthiest one
+	state.triedEndpoints = nil
+	return false
+}
+
+// startHealthChecks probes unhealthy base URLs until the client is closed
+func (c *Client) startHealthChecks() {
+	interval := c.healthCheckInterval
+	if interval <= 0 {
+		interval = defaultHealthCheckInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopHealthChecks = cancel
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, url := range c.endpoints.unhealthy() {
+					c.endpoints.recordCheck(url, c.checkEndpoint(ctx, url, interval))
+				}
+			}
+		}
+	}()
+}
+
+// checkEndpoint lists the models of a base URL and reports whether it answered
+// without a server error
+func (c *Client) checkEndpoint(ctx context.Context, baseURL string, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r := &Request{Method: http.MethodGet, Path: "/models"}
+	state := &callState{baseURL: baseURL}
+	if err := c.selectKey(ctx, r, state); err != nil {
+		return false
+	}
+
+	req, err := c.newRequest(ctx, r, nil, state)
+	if err != nil {
+		return false
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false
+	}
+	if _, err := readBody(resp); err != nil {
+		return false
+	}
+	return resp.StatusCode < http.StatusInternalServerError
+}
@@ -0,0 +1,145 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestEndpointFailoverOnServerError(t *testing.T) {
+	var primaryHits, backupHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryHits, 1)
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"message":"bad gateway"}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&backupHits, 1)
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer backup.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURLs(primary.URL, backup.URL),
+		deepseek.WithHealthCheckInterval(time.Hour),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	for i := 0; i < 2; i++ {
+		_, err = client.ListModels(context.Background())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&primaryHits))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&backupHits))
+
+	stats := client.EndpointStats()
+	require.Len(t, stats, 2)
+	assert.False(t, stats[0].Healthy)
+	assert.Equal(t, int64(1), stats[0].Failures)
+	assert.True(t, stats[1].Healthy)
+}
+
+func TestEndpointFailoverOnConnectionError(t *testing.T) {
+	dead := httptest.NewServer(http.NotFoundHandler())
+	deadURL := dead.URL
+	dead.Close()
+
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"is_available":true}`))
+	}))
+	defer backup.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURLs(deadURL, backup.URL),
+		deepseek.WithMaxRetries(0),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	balance, err := client.GetBalance(context.Background())
+	require.NoError(t, err)
+	assert.True(t, balance.IsAvailable)
+}
+
+func TestEndpointHealthCheckRestoresPrimary(t *testing.T) {
+	var down int32 = 1
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"primary"}]}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"object":"list","data":[{"id":"backup"}]}`))
+	}))
+	defer backup.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURLs(primary.URL, backup.URL),
+		deepseek.WithHealthCheckInterval(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	models, err := client.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "backup", models.Data[0].ID)
+
+	atomic.StoreInt32(&down, 0)
+	require.Eventually(t, func() bool {
+		return client.EndpointStats()[0].Healthy
+	}, time.Second, 5*time.Millisecond)
+
+	models, err = client.ListModels(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "primary", models.Data[0].ID)
+	assert.False(t, client.EndpointStats()[0].LastChecked.IsZero())
+}
+
+func TestEndpointRetryUsesRecoveredURL(t *testing.T) {
+	var primaryHits, backupHits int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&primaryHits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"down"}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The backup fails the first call, then recovers
+		if r.URL.Path == "/user/balance" && atomic.AddInt32(&backupHits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"down"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"is_available":true,"object":"list","data":[]}`))
+	}))
+	defer backup.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURLs(primary.URL, backup.URL),
+		deepseek.WithHealthCheckInterval(10*time.Millisecond),
+		deepseek.WithMaxRetries(1),
+		deepseek.WithRetryWaitTime(200*time.Millisecond),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Both URLs fail, and the retry goes to the backup once it is healthy again
+	balance, err := client.GetBalance(context.Background())
+	require.NoError(t, err)
+	assert.True(t, balance.IsAvailable)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&backupHits))
+	assert.False(t, client.EndpointStats()[0].Healthy)
+}
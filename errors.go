@@ -0,0 +1,55 @@
+package deepseek
+
+import "github.com/trustsight-io/deepseek-go/internal/errors"
+
+// Error types returned for failed calls. Inspect them with errors.As, or
+// classify them with errors.Is and the sentinel errors below.
+type (
+	// APIError is an error response of the API as decoded from its body
+	APIError = errors.APIError
+	// RequestError is returned for error statuses without a more specific type
+	RequestError = errors.RequestError
+	// InvalidRequestError is returned for invalid arguments and 403 responses
+	InvalidRequestError = errors.InvalidRequestError
+	// InvalidFormatError is returned for 400 responses
+	InvalidFormatError = errors.InvalidFormatError
+	// AuthenticationError is returned for 401 responses
+	AuthenticationError = errors.AuthenticationError
+	// InsufficientBalanceError is returned for 402 responses
+	InsufficientBalanceError = errors.InsufficientBalanceError
+	// InvalidParametersError is returned for 422 responses
+	InvalidParametersError = errors.InvalidParametersError
+	// ContextLengthExceededError is returned when the messages exceed the context window of the model
+	ContextLengthExceededError = errors.ContextLengthExceededError
+	// RateLimitError is returned for 429 responses
+	RateLimitError = errors.RateLimitError
+	// ServerError is returned for 500 responses
+	ServerError = errors.ServerError
+	// ServerOverloadedError is returned for 503 responses
+	ServerOverloadedError = errors.ServerOverloadedError
+	// ModelNotFoundError is returned when the requested model does not exist
+	ModelNotFoundError = errors.ModelNotFoundError
+)
+
+// Sentinel errors matched by errors.Is. InvalidFormatError, InvalidParametersError
+// and ContextLengthExceededError also match ErrInvalidRequest, and
+// ServerOverloadedError also matches ErrServer.
+var (
+	ErrInvalidRequest        = errors.ErrInvalidRequest
+	ErrInvalidFormat         = errors.ErrInvalidFormat
+	ErrAuthentication        = errors.ErrAuthentication
+	ErrInsufficientBalance   = errors.ErrInsufficientBalance
+	ErrInvalidParameters     = errors.ErrInvalidParameters
+	ErrContextLengthExceeded = errors.ErrContextLengthExceeded
+	ErrRateLimit             = errors.ErrRateLimit
+	ErrServer                = errors.ErrServer
+	ErrServerOverloaded      = errors.ErrServerOverloaded
+	ErrModelNotFound         = errors.ErrModelNotFound
+)
+
+// IsRetryable reports whether a failed call may succeed when it is sent again.
+// Rate limits, server failures and connection errors are retryable; invalid
+// requests, authentication and balance errors are not.
+func IsRetryable(err error) bool {
+	return errors.IsRetryable(err)
+}
@@ -0,0 +1,109 @@
+package deepseek_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestErrorTaxonomy(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		sentinel  error
+		target    interface{}
+		retryable bool
+	}{
+		{"invalid format", http.StatusBadRequest, `{"error":{"message":"bad json","type":"invalid_request_error"}}`, deepseek.ErrInvalidFormat, new(*deepseek.InvalidFormatError), false},
+		{"authentication", http.StatusUnauthorized, `{"error":{"message":"invalid key"}}`, deepseek.ErrAuthentication, new(*deepseek.AuthenticationError), false},
+		{"insufficient balance", http.StatusPaymentRequired, `{"error":{"message":"top up"}}`, deepseek.ErrInsufficientBalance, new(*deepseek.InsufficientBalanceError), false},
+		{"invalid parameters", http.StatusUnprocessableEntity, `{"error":{"message":"bad temperature","param":"temperature"}}`, deepseek.ErrInvalidParameters, new(*deepseek.InvalidParametersError), false},
+		{"rate limit", http.StatusTooManyRequests, `{"error":{"message":"slow down"}}`, deepseek.ErrRateLimit, new(*deepseek.RateLimitError), true},
+		{"server error", http.StatusInternalServerError, `{"error":{"message":"oops"}}`, deepseek.ErrServer, new(*deepseek.ServerError), true},
+		{"server overloaded", http.StatusServiceUnavailable, `overloaded`, deepseek.ErrServerOverloaded, new(*deepseek.ServerOverloadedError), true},
+		{
+			"context length exceeded", http.StatusBadRequest,
+			`{"error":{"message":"This model's maximum context length is 65536 tokens","code":"invalid_request_error"}}`,
+			deepseek.ErrContextLengthExceeded, new(*deepseek.ContextLengthExceededError), false,
+		},
+		{
+			"context length exceeded type", http.StatusUnprocessableEntity,
+			`{"error":{"message":"too long","type":"context_length_exceeded"}}`,
+			deepseek.ErrContextLengthExceeded, new(*deepseek.ContextLengthExceededError), false,
+		},
+		{
+			"server error mentioning context length", http.StatusInternalServerError,
+			`{"error":{"message":"worker crashed: context length exceeded while batching"}}`,
+			deepseek.ErrServer, new(*deepseek.ServerError), true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL), deepseek.WithMaxRetries(0))
+			require.NoError(t, err)
+
+			_, err = client.GetBalance(context.Background())
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, tt.sentinel), "errors.Is(%v, %v)", err, tt.sentinel)
+			assert.True(t, errors.As(err, tt.target), "errors.As(%v, %T)", err, tt.target)
+			assert.Equal(t, tt.retryable, deepseek.IsRetryable(err))
+
+			var apiErr *deepseek.APIError
+			require.True(t, errors.As(err, &apiErr), "errors.As(%v, *APIError)", err)
+			assert.Equal(t, tt.status, apiErr.StatusCode)
+		})
+	}
+}
+
+func TestErrorTaxonomyKeepsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":{"message":"bad role","type":"invalid_request_error","param":"messages[0].role","code":"invalid_value"}}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = client.GetBalance(context.Background())
+	var formatErr *deepseek.InvalidFormatError
+	require.ErrorAs(t, err, &formatErr)
+	var apiErr *deepseek.APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "bad role", apiErr.Message)
+	assert.Equal(t, "invalid_request_error", apiErr.Type)
+	assert.Equal(t, "messages[0].role", apiErr.Param)
+}
+
+func TestErrorTaxonomyHierarchy(t *testing.T) {
+	assert.True(t, errors.Is(&deepseek.ContextLengthExceededError{}, deepseek.ErrInvalidRequest))
+	assert.True(t, errors.Is(&deepseek.InvalidParametersError{}, deepseek.ErrInvalidRequest))
+	assert.True(t, errors.Is(&deepseek.ServerOverloadedError{}, deepseek.ErrServer))
+	assert.False(t, errors.Is(&deepseek.ServerError{}, deepseek.ErrServerOverloaded))
+	assert.False(t, deepseek.IsRetryable(context.Canceled))
+}
+
+func TestValidationErrorsAreTyped(t *testing.T) {
+	client, err := deepseek.NewClient("test-key")
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{})
+	var invalid *deepseek.InvalidRequestError
+	require.True(t, errors.As(err, &invalid))
+	assert.Equal(t, "messages", invalid.Param)
+	assert.True(t, errors.Is(err, deepseek.ErrInvalidRequest))
+}
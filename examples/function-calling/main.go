@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"os"
 
 	"github.com/trustsight-io/deepseek-go"
 )
@@ -30,12 +29,7 @@ func getCurrentWeather(location string) WeatherInfo {
 }
 
 func main() {
-	apiKey := os.Getenv("DEEPSEEK_API_KEY")
-	if apiKey == "" {
-		log.Fatal("DEEPSEEK_API_KEY environment variable is required")
-	}
-
-	client, err := deepseek.NewClient(apiKey)
+	client, err := deepseek.NewClientFromEnv()
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -56,56 +50,51 @@ func main() {
 		}`),
 	}
 
+	tools := []deepseek.Tool{{Type: deepseek.ToolTypeFunction, Function: &weatherFunction}}
+	messages := []deepseek.Message{
+		{
+			Role:    deepseek.RoleUser,
+			Content: "What's the weather like in Paris and in Tokyo?",
+		},
+	}
+
 	resp, err := client.CreateChatCompletion(
 		context.Background(),
 		&deepseek.ChatCompletionRequest{
-			Model: "deepseek-chat",
-			Messages: []deepseek.Message{
-				{
-					Role:    deepseek.RoleUser,
-					Content: "What's the weather like in Paris?",
-				},
-			},
-			Functions: []deepseek.Function{weatherFunction},
+			Model:      "deepseek-chat",
+			Messages:   messages,
+			Tools:      tools,
+			ToolChoice: deepseek.AutoToolChoice(),
 		},
 	)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	// Handle the function call
-	if resp.Choices[0].Message.FunctionCall != nil {
-		var args struct {
-			Location string `json:"location"`
-		}
-		if err := json.Unmarshal(resp.Choices[0].Message.FunctionCall.Arguments, &args); err != nil {
-			log.Fatal(err)
-		}
+	// Handle the tool calls, which the model may request several of at once
+	if calls := resp.Choices[0].Message.ToolCalls; len(calls) > 0 {
+		messages = append(messages, resp.Choices[0].Message)
+		for _, call := range calls {
+			var args struct {
+				Location string `json:"location"`
+			}
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
+				log.Fatal(err)
+			}
 
-		// Get the weather data
-		weather := getCurrentWeather(args.Location)
+			// Get the weather data and answer the call with its ID
+			weather := getCurrentWeather(args.Location)
+			messages = append(messages, deepseek.NewToolMessage(call.ID,
+				fmt.Sprintf("The current weather in %s is %.1f°%s and %s", weather.Location, weather.Temperature, weather.Unit, weather.Condition)))
+		}
 
-		// Send the function result back to continue the conversation
+		// Send the tool results back to continue the conversation
 		resp, err = client.CreateChatCompletion(
 			context.Background(),
 			&deepseek.ChatCompletionRequest{
-				Model: "deepseek-chat",
-				Messages: []deepseek.Message{
-					{
-						Role:    deepseek.RoleUser,
-						Content: "What's the weather like in Paris?",
-					},
-					{
-						Role:         deepseek.RoleAssistant,
-						Content:      "",
-						FunctionCall: resp.Choices[0].Message.FunctionCall,
-					},
-					{
-						Role:    deepseek.RoleFunction,
-						Name:    "get_current_weather",
-						Content: fmt.Sprintf("The current weather in %s is %.1f°%s and %s", weather.Location, weather.Temperature, weather.Unit, weather.Condition),
-					},
-				},
+				Model:    "deepseek-chat",
+				Messages: messages,
+				Tools:    tools,
 			},
 		)
 		if err != nil {
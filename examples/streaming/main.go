@@ -5,19 +5,13 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/trustsight-io/deepseek-go"
 )
 
 func main() {
-	apiKey := os.Getenv("DEEPSEEK_API_KEY")
-	if apiKey == "" {
-		log.Fatal("DEEPSEEK_API_KEY environment variable is required")
-	}
-
-	client, err := deepseek.NewClient(apiKey)
+	client, err := deepseek.NewClientFromEnv()
 	if err != nil {
 		log.Fatal(err)
 	}
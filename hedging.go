@@ -0,0 +1,252 @@
+package deepseek
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHedgeMinSamples = 20
+	hedgeLatencyWindow     = 200
+)
+
+// Hedging configures request hedging for non-streaming chat completions. When
+// a call has not answered within the hedge delay a duplicate is sent; the first
+// successful response wins and the other request is cancelled.
+type Hedging struct {
+	// Delay is how long to wait before sending the duplicate. With Percentile
+	// set it is only used until enough latencies have been observed.
+	Delay time.Duration
+	// Percentile derives the delay from observed latencies of successful calls,
+	// e.g. 0.95 hedges calls slower than the p95. Zero uses Delay only.
+	Percentile float64
+	// MinSamples is the number of observed latencies required before Percentile
+	// is used (default 20)
+	MinSamples int
+}
+
+// HedgeStats holds counters of the hedging policy
+type HedgeStats struct {
+	// Calls is the number of calls eligible for hedging
+	Calls int64
+	// Hedged is the number of calls for which a duplicate was sent
+	Hedged int64
+	// HedgeWins is the number of calls answered by the duplicate
+	HedgeWins int64
+	// LoserUsage is the token usage of losing requests that completed before
+	// they could be cancelled
+	LoserUsage Usage
+	// LoserEstimatedTokens is the estimated cost of losing requests cancelled
+	// in flight. Their usage is unknown, but the API may still bill them.
+	LoserEstimatedTokens int64
+}
+
+// WithHedging enables request hedging for non-streaming chat completions.
+// The usage of a losing request that completed anyway is reported to the
+// metrics collector and counted in HedgeStats. A loser cancelled in flight
+// keeps its estimated tokens in the rate limit budget and HedgeStats.
+func WithHedging(cfg Hedging) ClientOption {
+	return func(c *Client) {
+		c.hedging = newHedger(cfg)
+	}
+}
+
+// HedgeStats returns the counters of the hedging policy. It returns the zero
+// value when hedging is not enabled.
+func (c *Client) HedgeStats() HedgeStats {
+	if c.hedging == nil {
+		return HedgeStats{}
+	}
+	c.hedging.mu.Lock()
+	defer c.hedging.mu.Unlock()
+	return c.hedging.stats
+}
+
+// hedger tracks the latencies that drive the hedge delay
+type hedger struct {
+	mu        sync.Mutex
+	cfg       Hedging
+	latencies []time.Duration
+	next      int
+	stats     HedgeStats
+}
+
+func newHedger(cfg Hedging) *hedger {
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = defaultHedgeMinSamples
+	}
+	return &hedger{cfg: cfg}
+}
+
+// delay returns how long to wait before hedging. It reports false when there is
+// no delay to hedge with yet.
+func (h *hedger) delay() (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cfg.Percentile > 0 && len(h.latencies) >= h.cfg.MinSamples {
+		sorted := append([]time.Duration(nil), h.latencies...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		i := int(h.cfg.Percentile * float64(len(sorted)))
+		if i >= len(sorted) {
+			i = len(sorted) - 1
+		}
+		return sorted[i], true
+	}
+	return h.cfg.Delay, h.cfg.Delay > 0
+}
+
+// observe records the latency of a successful call
+func (h *hedger) observe(d time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.latencies) < hedgeLatencyWindow {
+		h.latencies = append(h.latencies, d)
+		return
+	}
+	h.latencies[h.next] = d
+	h.next = (h.next + 1) % hedgeLatencyWindow
+}
+
+// shouldHedge reports whether a call is eligible for hedging
+func (c *Client) shouldHedge(req *Request) bool {
+	return c.hedging != nil && !req.Stream && req.Method == http.MethodPost && req.Path == "/chat/completions"
+}
+
+// hedgeResult is the outcome of one of the requests of a hedged call
+type hedgeResult struct {
+	req     *Request
+	state   *callState
+	resp    *Response
+	err     error
+	elapsed time.Duration
+	hedge   bool
+}
+
+// hedgedExchange sends a call and, if it has not answered within the hedge
+// delay, a duplicate. The first success wins; the other request is cancelled
+// and reported once it finishes.
+func (c *Client) hedgedExchange(ctx context.Context, req *Request, body *payload, state *callState) (*Response, error) {
+	delay, ok := c.hedging.delay()
+	c.hedging.mu.Lock()
+	c.hedging.stats.Calls++
+	c.hedging.mu.Unlock()
+
+	start := time.Now()
+	results := make(chan hedgeResult, 2)
+	run := func(ctx context.Context, r *Request, s *callState, hedge bool) {
+		resp, err := c.exchange(ctx, r, body, s)
+		results <- hedgeResult{req: r, state: s, resp: resp, err: err, elapsed: time.Since(start), hedge: hedge}
+	}
+
+	primaryCtx, cancelPrimary := context.WithCancel(ctx)
+	defer cancelPrimary()
+	// Both requests decode into their own value; only the winner is copied
+	// into the caller's, as the loser may still be running
+	go run(primaryCtx, duplicateRequest(req), &callState{span: state.span}, false)
+	pending := 1
+
+	var timer <-chan time.Time
+	if ok {
+		t := time.NewTimer(delay)
+		defer t.Stop()
+		timer = t.C
+	}
+
+	cancelHedge := func() {}
+	var result hedgeResult
+	for {
+		select {
+		case <-timer:
+			timer = nil
+			var hedgeCtx context.Context
+			hedgeCtx, cancelHedge = context.WithCancel(ctx)
+			defer cancelHedge()
+			state.span.AddEvent(EventHedge, Attribute{"delay", delay.String()})
+			c.log(ctx, slog.LevelDebug, "deepseek: hedging request",
+				slog.String("path", req.Path),
+				slog.Duration("delay", delay),
+			)
+			go run(hedgeCtx, duplicateRequest(req), &callState{span: noopSpan{}}, true)
+			pending++
+			c.hedging.mu.Lock()
+			c.hedging.stats.Hedged++
+			c.hedging.mu.Unlock()
+			continue
+		case result = <-results:
+			pending--
+		}
+		if result.err == nil || pending == 0 {
+			break
+		}
+		// Give the other request the chance to succeed
+	}
+
+	if pending > 0 {
+		// Cancel the loser and report it once it has finished
+		cancelPrimary()
+		cancelHedge()
+		go func() {
+			c.reportHedgeLoser(<-results)
+		}()
+	}
+
+	if result.err == nil {
+		c.hedging.observe(result.elapsed)
+		if result.hedge {
+			c.hedging.mu.Lock()
+			c.hedging.stats.HedgeWins++
+			c.hedging.mu.Unlock()
+		}
+		if req.result != nil {
+			if err := assignResult(req.result, result.resp.Body); err != nil {
+				return nil, err
+			}
+			result.resp.Body = req.result
+		}
+	}
+
+	span := state.span
+	*state = *result.state
+	state.span = span
+	return result.resp, result.err
+}
+
+// duplicateRequest copies a call for hedging, with its own response value
+func duplicateRequest(req *Request) *Request {
+	dup := *req
+	if req.result != nil {
+		dup.result = reflect.New(reflect.TypeOf(req.result).Elem()).Interface()
+	}
+	return &dup
+}
+
+// reportHedgeLoser reports the usage of a losing request that completed anyway,
+// or the estimated cost of one cancelled after it was sent
+func (c *Client) reportHedgeLoser(r hedgeResult) {
+	if r.err != nil || r.resp == nil {
+		if errors.Is(r.err, context.Canceled) && r.state.attempts > 0 {
+			_, tokens := c.requestCost(r.req.Body)
+			c.hedging.mu.Lock()
+			c.hedging.stats.LoserEstimatedTokens += int64(tokens)
+			c.hedging.mu.Unlock()
+		}
+		return
+	}
+	usage, ok := responseUsage(r.resp.Body)
+	if !ok {
+		return
+	}
+
+	c.hedging.mu.Lock()
+	c.hedging.stats.LoserUsage.add(usage)
+	c.hedging.mu.Unlock()
+	c.recordRequest(r.req, r.state, r.resp, nil, r.elapsed)
+}
@@ -0,0 +1,232 @@
+package deepseek_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestHedgingFirstSuccessWins(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		if atomic.AddInt32(&hits, 1) == 1 {
+			// The first request hangs until it is cancelled
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"hedge","choices":[{"index":0,"message":{"role":"assistant","content":"fast"}}],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithHedging(deepseek.Hedging{Delay: 20 * time.Millisecond}),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, "hedge", resp.ID)
+	assert.Equal(t, "fast", resp.Choices[0].Message.Content)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+
+	stats := client.HedgeStats()
+	assert.Equal(t, int64(1), stats.Calls)
+	assert.Equal(t, int64(1), stats.Hedged)
+	assert.Equal(t, int64(1), stats.HedgeWins)
+	assert.Equal(t, 0, stats.LoserUsage.TotalTokens)
+
+	// The cancelled primary may still be billed, so its estimate is counted
+	require.Eventually(t, func() bool {
+		return client.HedgeStats().LoserEstimatedTokens > 0
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestHedgingCompletedLoserUsage(t *testing.T) {
+	release := make(chan struct{})
+	var hits int32
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL("http://deepseek.test"),
+		deepseek.WithHedging(deepseek.Hedging{Delay: 20 * time.Millisecond}),
+		deepseek.WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			body := `{"id":"hedge","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`
+			if atomic.AddInt32(&hits, 1) == 1 {
+				// The primary completes after losing, before it notices the cancellation
+				<-release
+				body = `{"id":"primary","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, nil
+		})}),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hedge", resp.ID)
+	close(release)
+
+	require.Eventually(t, func() bool {
+		return client.HedgeStats().LoserUsage.TotalTokens == 7
+	}, time.Second, 5*time.Millisecond)
+	stats := client.HedgeStats()
+	assert.Equal(t, 4, stats.LoserUsage.CompletionTokens)
+	assert.Equal(t, int64(0), stats.LoserEstimatedTokens)
+}
+
+func TestHedgingWinnerDoesNotWaitForLoser(t *testing.T) {
+	release := make(chan struct{})
+	// Release the primary eventually even if the call waits for it
+	timer := time.AfterFunc(2*time.Second, func() { close(release) })
+	defer func() {
+		if timer.Stop() {
+			close(release)
+		}
+	}()
+
+	var hits int32
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL("http://deepseek.test"),
+		deepseek.WithHedging(deepseek.Hedging{Delay: 20 * time.Millisecond}),
+		deepseek.WithHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&hits, 1) == 1 {
+				// The primary ignores cancellation and only finishes once released
+				<-release
+				return nil, io.ErrUnexpectedEOF
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader(`{"id":"hedge","choices":[]}`)),
+			}, nil
+		})}),
+	)
+	require.NoError(t, err)
+
+	start := time.Now()
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "hedge", resp.ID)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestHedgingNotTriggeredForFastCalls(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		_, _ = w.Write([]byte(`{"id":"primary","choices":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithHedging(deepseek.Hedging{Delay: time.Second}),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "primary", resp.ID)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+	assert.Equal(t, int64(0), client.HedgeStats().Hedged)
+}
+
+func TestHedgingPercentileDelay(t *testing.T) {
+	var hits, slow int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		atomic.AddInt32(&hits, 1)
+		if atomic.CompareAndSwapInt32(&slow, 1, 0) {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"chat","choices":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithHedging(deepseek.Hedging{Percentile: 0.5, MinSamples: 3}),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	}
+	// Without Delay nothing is hedged until enough latencies were observed
+	for i := 0; i < 3; i++ {
+		_, err = client.CreateChatCompletion(context.Background(), req)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int64(0), client.HedgeStats().Hedged)
+
+	atomic.StoreInt32(&slow, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.CreateChatCompletion(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), client.HedgeStats().Hedged)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&hits))
+}
+
+func TestHedgingCancelledLoserKeepsRateLimitEstimate(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		if atomic.AddInt32(&hits, 1) == 1 {
+			<-r.Context().Done()
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"hedge","choices":[],"usage":{"total_tokens":5}}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithHedging(deepseek.Hedging{Delay: 20 * time.Millisecond}),
+		deepseek.WithRateLimit(deepseek.RateLimit{TokensPerMinute: 1000}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages:  []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+		MaxTokens: 450,
+	})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return client.HedgeStats().LoserEstimatedTokens > 0
+	}, time.Second, 5*time.Millisecond)
+
+	// The cancelled primary still holds its estimate, so a call needing more
+	// than the rest of the budget has to wait
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateChatCompletion(ctx, &deepseek.ChatCompletionRequest{
+		Messages:  []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+		MaxTokens: 560,
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
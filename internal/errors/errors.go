@@ -3,22 +3,59 @@
 package errors
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 // Error types
 const (
 	ErrorTypeInvalidRequest        = "invalid_request"
+	ErrorTypeInvalidFormat         = "invalid_format"
 	ErrorTypeAuthentication        = "authentication"
+	ErrorTypeInsufficientBalance   = "insufficient_balance"
 	ErrorTypePermission            = "permission"
+	ErrorTypeInvalidParameters     = "invalid_parameters"
 	ErrorTypeRateLimit             = "rate_limit"
 	ErrorTypeServer                = "server_error"
+	ErrorTypeServerOverloaded      = "server_overloaded"
 	ErrorTypeModelNotFound         = "model_not_found"
 	ErrorTypeContextLengthExceeded = "context_length_exceeded"
 	ErrorTypeTokenLimitExceeded    = "token_limit_exceeded"
 )
 
+// Sentinel errors matched by errors.Is against the typed errors below
+var (
+	ErrInvalidRequest        = errors.New("deepseek: invalid request")
+	ErrInvalidFormat         = errors.New("deepseek: invalid request format")
+	ErrAuthentication        = errors.New("deepseek: authentication failed")
+	ErrInsufficientBalance   = errors.New("deepseek: insufficient balance")
+	ErrInvalidParameters     = errors.New("deepseek: invalid parameters")
+	ErrRateLimit             = errors.New("deepseek: rate limit exceeded")
+	ErrServer                = errors.New("deepseek: server error")
+	ErrServerOverloaded      = errors.New("deepseek: server overloaded")
+	ErrModelNotFound         = errors.New("deepseek: model not found")
+	ErrContextLengthExceeded = errors.New("deepseek: context length exceeded")
+)
+
+// IsRetryable reports whether err, or an error it wraps, is classified as retryable
+func IsRetryable(err error) bool {
+	var r interface{ IsRetryable() bool }
+	if errors.As(err, &r) {
+		return r.IsRetryable()
+	}
+	return false
+}
+
+// retryableStatus reports whether a request that failed with the given status may succeed when retried
+func retryableStatus(statusCode int) bool {
+	return statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError
+}
+
 // APIError represents an error returned by the DeepSeek API
 type APIError struct {
 	Code       int    `json:"code"`
@@ -33,6 +70,54 @@ func (e *APIError) Error() string {
 	return fmt.Sprintf("deepseek: %s (code: %d, type: %s, status: %d)", e.Message, e.Code, e.Type, e.StatusCode)
 }
 
+// IsRetryable reports whether the status of the error is worth retrying
+func (e *APIError) IsRetryable() bool {
+	return retryableStatus(e.StatusCode)
+}
+
+// ParseAPIError decodes an error response body. Both the flat form and the
+// OpenAI-compatible {"error": {...}} form are accepted, with numeric or string codes.
+func ParseAPIError(statusCode int, body []byte) (*APIError, error) {
+	var raw struct {
+		Error *json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, err
+	}
+	if raw.Error != nil && len(*raw.Error) > 0 {
+		switch (*raw.Error)[0] {
+		case '{':
+			body = *raw.Error
+		case '"':
+			apiErr := &APIError{StatusCode: statusCode}
+			if err := json.Unmarshal(*raw.Error, &apiErr.Message); err != nil {
+				return nil, err
+			}
+			return apiErr, nil
+		}
+	}
+
+	var fields struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Type    string          `json:"type"`
+		Param   *string         `json:"param"`
+	}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, err
+	}
+
+	apiErr := &APIError{Message: fields.Message, Type: fields.Type, StatusCode: statusCode}
+	if fields.Param != nil {
+		apiErr.Param = *fields.Param
+	}
+	var code string
+	if err := json.Unmarshal(fields.Code, &apiErr.Code); err != nil && json.Unmarshal(fields.Code, &code) == nil && apiErr.Type == "" {
+		apiErr.Type = code
+	}
+	return apiErr, nil
+}
+
 // RequestError represents an error that occurred while making a request
 type RequestError struct {
 	StatusCode int
@@ -47,6 +132,11 @@ func (e *RequestError) Unwrap() error {
 	return e.Err
 }
 
+// IsRetryable reports whether the status of the error is worth retrying
+func (e *RequestError) IsRetryable() bool {
+	return retryableStatus(e.StatusCode)
+}
+
 // InvalidRequestError represents an error due to invalid request parameters
 type InvalidRequestError struct {
 	Param string
@@ -64,6 +154,39 @@ func (e *InvalidRequestError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is ErrInvalidRequest
+func (e *InvalidRequestError) Is(target error) bool {
+	return target == ErrInvalidRequest
+}
+
+// IsRetryable returns false; the request must be fixed first
+func (e *InvalidRequestError) IsRetryable() bool {
+	return false
+}
+
+// InvalidFormatError represents a 400 response for a malformed request body
+type InvalidFormatError struct {
+	Err error
+}
+
+func (e *InvalidFormatError) Error() string {
+	return fmt.Sprintf("deepseek: invalid request format: %v", e.Err)
+}
+
+func (e *InvalidFormatError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrInvalidFormat or ErrInvalidRequest
+func (e *InvalidFormatError) Is(target error) bool {
+	return target == ErrInvalidFormat || target == ErrInvalidRequest
+}
+
+// IsRetryable returns false; the request must be fixed first
+func (e *InvalidFormatError) IsRetryable() bool {
+	return false
+}
+
 // AuthenticationError represents an authentication error
 type AuthenticationError struct {
 	Err error
@@ -77,6 +200,89 @@ func (e *AuthenticationError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is ErrAuthentication
+func (e *AuthenticationError) Is(target error) bool {
+	return target == ErrAuthentication
+}
+
+// IsRetryable returns false; the API key must be fixed first
+func (e *AuthenticationError) IsRetryable() bool {
+	return false
+}
+
+// InsufficientBalanceError represents a 402 response for an account that ran out of balance
+type InsufficientBalanceError struct {
+	Err error
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("deepseek: insufficient balance: %v", e.Err)
+}
+
+func (e *InsufficientBalanceError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrInsufficientBalance
+func (e *InsufficientBalanceError) Is(target error) bool {
+	return target == ErrInsufficientBalance
+}
+
+// IsRetryable returns false; the account must be topped up first
+func (e *InsufficientBalanceError) IsRetryable() bool {
+	return false
+}
+
+// InvalidParametersError represents a 422 response for invalid request parameters
+type InvalidParametersError struct {
+	Param string
+	Err   error
+}
+
+func (e *InvalidParametersError) Error() string {
+	if e.Param != "" {
+		return fmt.Sprintf("deepseek: invalid parameter '%s': %v", e.Param, e.Err)
+	}
+	return fmt.Sprintf("deepseek: invalid parameters: %v", e.Err)
+}
+
+func (e *InvalidParametersError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrInvalidParameters or ErrInvalidRequest
+func (e *InvalidParametersError) Is(target error) bool {
+	return target == ErrInvalidParameters || target == ErrInvalidRequest
+}
+
+// IsRetryable returns false; the request must be fixed first
+func (e *InvalidParametersError) IsRetryable() bool {
+	return false
+}
+
+// ContextLengthExceededError represents a request whose messages exceed the context window of the model
+type ContextLengthExceededError struct {
+	Err error
+}
+
+func (e *ContextLengthExceededError) Error() string {
+	return fmt.Sprintf("deepseek: context length exceeded: %v", e.Err)
+}
+
+func (e *ContextLengthExceededError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrContextLengthExceeded or ErrInvalidRequest
+func (e *ContextLengthExceededError) Is(target error) bool {
+	return target == ErrContextLengthExceeded || target == ErrInvalidRequest
+}
+
+// IsRetryable returns false; the messages must be shortened first
+func (e *ContextLengthExceededError) IsRetryable() bool {
+	return false
+}
+
 // RateLimitError represents a rate limit error
 type RateLimitError struct {
 	RetryAfter int
@@ -91,6 +297,63 @@ func (e *RateLimitError) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether target is ErrRateLimit
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimit
+}
+
+// IsRetryable returns true; the request can be retried after RetryAfter seconds
+func (e *RateLimitError) IsRetryable() bool {
+	return true
+}
+
+// ServerError represents a 5xx response caused by a server failure
+type ServerError struct {
+	StatusCode int
+	Err        error
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("deepseek: server error with status %d: %v", e.StatusCode, e.Err)
+}
+
+func (e *ServerError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrServer
+func (e *ServerError) Is(target error) bool {
+	return target == ErrServer
+}
+
+// IsRetryable returns true; server failures are usually transient
+func (e *ServerError) IsRetryable() bool {
+	return true
+}
+
+// ServerOverloadedError represents a 503 response sent while the server is overloaded
+type ServerOverloadedError struct {
+	Err error
+}
+
+func (e *ServerOverloadedError) Error() string {
+	return fmt.Sprintf("deepseek: server overloaded: %v", e.Err)
+}
+
+func (e *ServerOverloadedError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrServerOverloaded or ErrServer
+func (e *ServerOverloadedError) Is(target error) bool {
+	return target == ErrServerOverloaded || target == ErrServer
+}
+
+// IsRetryable returns true; the request can be retried once the load drops
+func (e *ServerOverloadedError) IsRetryable() bool {
+	return true
+}
+
 // ModelNotFoundError represents a model not found error
 type ModelNotFoundError struct {
 	Model string
@@ -105,18 +368,55 @@ func (e *ModelNotFoundError) Unwrap() error {
 	return e.Err
 }
 
-// HandleErrorResp creates an appropriate error type based on the response
+// Is reports whether target is ErrModelNotFound
+func (e *ModelNotFoundError) Is(target error) bool {
+	return target == ErrModelNotFound
+}
+
+// IsRetryable returns false; the model name must be fixed first
+func (e *ModelNotFoundError) IsRetryable() bool {
+	return false
+}
+
+// isContextLengthExceeded reports whether an API error was caused by a too long context
+func isContextLengthExceeded(apiErr *APIError) bool {
+	if apiErr.Type == ErrorTypeContextLengthExceeded {
+		return true
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "maximum context length") || strings.Contains(msg, "context length exceeded")
+}
+
+// HandleErrorResp creates an appropriate error type based on the response. The
+// typed error wraps apiErr, so errors.As still yields the code, type and param.
 func HandleErrorResp(resp *http.Response, apiErr *APIError) error {
+	if apiErr.StatusCode == 0 {
+		apiErr.StatusCode = resp.StatusCode
+	}
+	var err error = apiErr
+	// Only rejected requests can be too long; a 5xx mentioning the context
+	// length stays a retryable server error
+	rejected := resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity
+	if rejected && isContextLengthExceeded(apiErr) {
+		return &ContextLengthExceededError{Err: err}
+	}
+
 	switch resp.StatusCode {
+	case http.StatusBadRequest:
+		return &InvalidFormatError{Err: err}
 	case http.StatusUnauthorized:
-		return &AuthenticationError{Err: fmt.Errorf("%s", apiErr.Message)}
+		return &AuthenticationError{Err: err}
+	case http.StatusPaymentRequired:
+		return &InsufficientBalanceError{Err: err}
 	case http.StatusForbidden:
-		return &InvalidRequestError{Param: apiErr.Param, Err: fmt.Errorf("%s", apiErr.Message)}
+		return &InvalidRequestError{Param: apiErr.Param, Err: err}
 	case http.StatusNotFound:
 		if apiErr.Type == ErrorTypeModelNotFound {
-			return &ModelNotFoundError{Model: apiErr.Param, Err: fmt.Errorf("%s", apiErr.Message)}
+			return &ModelNotFoundError{Model: apiErr.Param, Err: err}
 		}
-		return &RequestError{StatusCode: resp.StatusCode, Err: fmt.Errorf("%s", apiErr.Message)}
+		return &RequestError{StatusCode: resp.StatusCode, Err: err}
+	case http.StatusUnprocessableEntity:
+		return &InvalidParametersError{Param: apiErr.Param, Err: err}
 	case http.StatusTooManyRequests:
 		retryAfter := 0
 		if s := resp.Header.Get("Retry-After"); s != "" {
@@ -125,11 +425,15 @@ func HandleErrorResp(resp *http.Response, apiErr *APIError) error {
 				retryAfter = 60
 			}
 		}
-		return &RateLimitError{RetryAfter: retryAfter, Err: fmt.Errorf("%s", apiErr.Message)}
+		return &RateLimitError{RetryAfter: retryAfter, Err: err}
+	case http.StatusInternalServerError:
+		return &ServerError{StatusCode: resp.StatusCode, Err: err}
+	case http.StatusServiceUnavailable:
+		return &ServerOverloadedError{Err: err}
 	default:
 		return &RequestError{
 			StatusCode: resp.StatusCode,
-			Err:        fmt.Errorf("%s", apiErr.Message),
+			Err:        err,
 		}
 	}
 }
@@ -0,0 +1,251 @@
+package deepseek
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultKeyCooldown = time.Minute
+
+// KeyStrategy selects how requests are spread across the keys of a pool
+type KeyStrategy int
+
+const (
+	// KeyStrategyRoundRobin uses the keys in turn
+	KeyStrategyRoundRobin KeyStrategy = iota
+	// KeyStrategyWeighted uses the keys in proportion to their weights
+	KeyStrategyWeighted
+)
+
+// APIKey is a key in a key pool
+type APIKey struct {
+	// Key is the API key
+	Key string
+	// Weight is the relative share of requests for KeyStrategyWeighted (default 1)
+	Weight int
+}
+
+// KeyPool configures rotation and failover across several API keys
+type KeyPool struct {
+	// Keys are the keys in the pool
+	Keys []APIKey
+	// Strategy selects how requests are spread across the keys
+	Strategy KeyStrategy
+	// Cooldown is how long a key is taken out of rotation after a failure (default 1 minute)
+	Cooldown time.Duration
+}
+
+// KeyStats describes the usage and health of a key in the pool
+type KeyStats struct {
+	// Key is the masked API key
+	Key string
+	// Requests is the number of requests sent with the key
+	Requests int64
+	// Failures is the number of failures that took the key out of rotation
+	Failures int64
+	// TotalTokens is the number of tokens reported as used by the key
+	TotalTokens int64
+	// Healthy reports whether the key is in rotation
+	Healthy bool
+	// DisabledUntil is when the key returns to rotation if it is unhealthy
+	DisabledUntil time.Time
+	// LastError is the last error that took the key out of rotation
+	LastError string
+}
+
+// WithKeyPool spreads requests across several API keys. A key that gets an
+// authentication, rate limit or insufficient balance error is taken out of
+// rotation for the cooldown and the request is retried on the next key.
+// The pool replaces the key passed to NewClient, which may then be empty.
+func WithKeyPool(pool KeyPool) ClientOption {
+	return func(c *Client) {
+		c.keys = newKeyPool(pool)
+	}
+}
+
+// KeyStats returns the usage and health of every key in the pool.
+// It returns nil when no key pool is configured.
+func (c *Client) KeyStats() []KeyStats {
+	if c.keys == nil {
+		return nil
+	}
+	return c.keys.stats()
+}
+
+// keyPool tracks the keys of a pool and their health
+type keyPool struct {
+	mu       sync.Mutex
+	strategy KeyStrategy
+	cooldown time.Duration
+	keys     []*pooledKey
+	next     int
+}
+
+// pooledKey is a key and its bookkeeping
+type pooledKey struct {
+	key           string
+	weight        int
+	current       int
+	requests      int64
+	failures      int64
+	totalTokens   int64
+	disabledUntil time.Time
+	lastError     string
+}
+
+func newKeyPool(pool KeyPool) *keyPool {
+	if pool.Cooldown <= 0 {
+		pool.Cooldown = defaultKeyCooldown
+	}
+
+	p := &keyPool{strategy: pool.Strategy, cooldown: pool.Cooldown}
+	for _, k := range pool.Keys {
+		if k.Key == "" {
+			continue
+		}
+		weight := k.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		p.keys = append(p.keys, &pooledKey{key: k.Key, weight: weight})
+	}
+	return p
+}
+
+// acquire selects the key for the next attempt, skipping the keys in exclude.
+// When every remaining key is out of rotation, the one returning soonest is used.
+// It returns false when every key has been excluded.
+func (p *keyPool) acquire(exclude map[string]bool) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var candidates []*pooledKey
+	var fallback *pooledKey
+	for _, k := range p.keys {
+		if exclude[k.key] {
+			continue
+		}
+		if now.Before(k.disabledUntil) {
+			if fallback == nil || k.disabledUntil.Before(fallback.disabledUntil) {
+				fallback = k
+			}
+			continue
+		}
+		candidates = append(candidates, k)
+	}
+
+	var selected *pooledKey
+	switch {
+	case len(candidates) == 0 && fallback == nil:
+		return "", false
+	case len(candidates) == 0:
+		selected = fallback
+	case p.strategy == KeyStrategyWeighted:
+		selected = p.nextWeighted(candidates)
+	default:
+		selected = p.nextRoundRobin(candidates)
+	}
+
+	selected.requests++
+	return selected.key, true
+}
+
+// nextRoundRobin returns the first candidate at or after the round-robin position
+func (p *keyPool) nextRoundRobin(candidates []*pooledKey) *pooledKey {
+	for i := range p.keys {
+		idx := (p.next + i) % len(p.keys)
+		for _, k := range candidates {
+			if k == p.keys[idx] {
+				p.next = idx + 1
+				return k
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// nextWeighted implements smooth weighted round-robin over the candidates
+func (p *keyPool) nextWeighted(candidates []*pooledKey) *pooledKey {
+	var total int
+	var best *pooledKey
+	for _, k := range candidates {
+		k.current += k.weight
+		total += k.weight
+		if best == nil || k.current > best.current {
+			best = k
+		}
+	}
+	best.current -= total
+	return best
+}
+
+// shouldFailover reports whether a response status means the key itself is unusable
+func shouldFailover(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized ||
+		statusCode == http.StatusPaymentRequired ||
+		statusCode == http.StatusTooManyRequests
+}
+
+// disable takes a key out of rotation after a failure
+func (p *keyPool) disable(key string, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.key == key {
+			k.failures++
+			k.disabledUntil = time.Now().Add(p.cooldown)
+			if err != nil {
+				k.lastError = err.Error()
+			}
+			return
+		}
+	}
+}
+
+// recordUsage adds the tokens used by a call to its key
+func (p *keyPool) recordUsage(key string, tokens int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, k := range p.keys {
+		if k.key == key {
+			k.totalTokens += int64(tokens)
+			return
+		}
+	}
+}
+
+func (p *keyPool) stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, k := range p.keys {
+		s := KeyStats{
+			Key:         maskKey(k.key),
+			Requests:    k.requests,
+			Failures:    k.failures,
+			TotalTokens: k.totalTokens,
+			Healthy:     !now.Before(k.disabledUntil),
+			LastError:   k.lastError,
+		}
+		if !s.Healthy {
+			s.DisabledUntil = k.disabledUntil
+		}
+		stats = append(stats, s)
+	}
+	return stats
+}
+
+// maskKey hides all but the last four characters of an API key
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return "****"
+	}
+	return fmt.Sprintf("%s...%s", key[:3], key[len(key)-4:])
+}
@@ -0,0 +1,177 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+// keyRecorder is a test server that records the keys it receives
+type keyRecorder struct {
+	mu   sync.Mutex
+	seen []string
+	// status maps a key to the error status returned for it
+	status map[string]int
+}
+
+func (k *keyRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get("Authorization")
+	k.mu.Lock()
+	k.seen = append(k.seen, key)
+	status := k.status[key]
+	k.mu.Unlock()
+
+	if status != 0 {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"message":"key rejected"}`))
+		return
+	}
+	_, _ = w.Write([]byte(`{"id":"chat-1","choices":[{"message":{"content":"hi"}}],"usage":{"total_tokens":7}}`))
+}
+
+func TestKeyPoolRoundRobin(t *testing.T) {
+	recorder := &keyRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithKeyPool(deepseek.KeyPool{
+			Keys: []deepseek.APIKey{{Key: "sk-first-key"}, {Key: "sk-second-key"}},
+		}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		_, err = client.ListModels(context.Background())
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		"Bearer sk-first-key", "Bearer sk-second-key",
+		"Bearer sk-first-key", "Bearer sk-second-key",
+	}, recorder.seen)
+}
+
+func TestKeyPoolWeighted(t *testing.T) {
+	recorder := &keyRecorder{}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithKeyPool(deepseek.KeyPool{
+			Keys:     []deepseek.APIKey{{Key: "sk-heavy-key", Weight: 3}, {Key: "sk-light-key", Weight: 1}},
+			Strategy: deepseek.KeyStrategyWeighted,
+		}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 8; i++ {
+		_, err = client.GetBalance(context.Background())
+		require.NoError(t, err)
+	}
+
+	stats := client.KeyStats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, int64(6), stats[0].Requests)
+	assert.Equal(t, int64(2), stats[1].Requests)
+}
+
+func TestKeyPoolFailover(t *testing.T) {
+	recorder := &keyRecorder{status: map[string]int{
+		"Bearer sk-revoked-key": http.StatusUnauthorized,
+		"Bearer sk-broke-key":   http.StatusPaymentRequired,
+	}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithKeyPool(deepseek.KeyPool{
+			Keys: []deepseek.APIKey{{Key: "sk-revoked-key"}, {Key: "sk-broke-key"}, {Key: "sk-working-key"}},
+		}),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	}
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "chat-1", resp.ID)
+	assert.Equal(t, []string{"Bearer sk-revoked-key", "Bearer sk-broke-key", "Bearer sk-working-key"}, recorder.seen)
+
+	// Unhealthy keys stay out of rotation
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer sk-working-key", recorder.seen[3])
+
+	stats := client.KeyStats()
+	require.Len(t, stats, 3)
+	assert.False(t, stats[0].Healthy)
+	assert.Contains(t, stats[0].LastError, "authentication failed")
+	assert.False(t, stats[1].Healthy)
+	assert.True(t, stats[2].Healthy)
+	assert.Equal(t, int64(14), stats[2].TotalTokens)
+	assert.Equal(t, "sk-...-key", stats[2].Key)
+}
+
+func TestKeyPoolAllKeysFail(t *testing.T) {
+	recorder := &keyRecorder{status: map[string]int{
+		"Bearer sk-first-key":  http.StatusUnauthorized,
+		"Bearer sk-second-key": http.StatusUnauthorized,
+	}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithKeyPool(deepseek.KeyPool{
+			Keys: []deepseek.APIKey{{Key: "sk-first-key"}, {Key: "sk-second-key"}},
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ListModels(context.Background())
+	assert.ErrorContains(t, err, "authentication failed")
+	assert.Len(t, recorder.seen, 2)
+}
+
+func TestKeyPoolAllKeysRateLimited(t *testing.T) {
+	recorder := &keyRecorder{status: map[string]int{
+		"Bearer sk-first-key":  http.StatusTooManyRequests,
+		"Bearer sk-second-key": http.StatusTooManyRequests,
+	}}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(1),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+		deepseek.WithKeyPool(deepseek.KeyPool{
+			Keys: []deepseek.APIKey{{Key: "sk-first-key"}, {Key: "sk-second-key"}},
+		}),
+	)
+	require.NoError(t, err)
+
+	// The retry goes through the pool again and the API error is returned
+	_, err = client.ListModels(context.Background())
+	var rateErr *deepseek.RateLimitError
+	assert.ErrorAs(t, err, &rateErr)
+	assert.ErrorIs(t, err, deepseek.ErrRateLimit)
+	assert.Len(t, recorder.seen, 4)
+}
+
+func TestKeyPoolEmpty(t *testing.T) {
+	_, err := deepseek.NewClient("", deepseek.WithKeyPool(deepseek.KeyPool{}))
+	assert.Error(t, err)
+}
@@ -0,0 +1,203 @@
+package deepseek
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// LogRedaction configures what is hidden from logs
+type LogRedaction struct {
+	// Headers lists extra headers whose values are replaced in logs. The header
+	// carrying the API key is always redacted.
+	Headers []string
+	// MaskContent replaces message contents, prompts and function arguments in logged payloads
+	MaskContent bool
+}
+
+// redactedValue replaces the value of redacted headers
+const redactedValue = "[REDACTED]"
+
+// maskedFields are the payload fields replaced when LogRedaction.MaskContent is set
+var maskedFields = map[string]bool{
+	"content":           true,
+	"reasoning_content": true,
+	"prompt":            true,
+	"text":              true,
+	"arguments":         true,
+}
+
+// WithLogger sets the structured logger used for request and response summaries,
+// retries, stream lifecycle events and errors. Full payloads are logged at debug level.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogRedaction configures what is hidden from logs
+func WithLogRedaction(redaction LogRedaction) ClientOption {
+	return func(c *Client) {
+		c.redaction = redaction
+	}
+}
+
+// defaultDebugLogger is used by WithDebug when no logger is set
+func defaultDebugLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// logEnabled reports whether the client logs at the given level
+func (c *Client) logEnabled(ctx context.Context, level slog.Level) bool {
+	return c.logger != nil && c.logger.Enabled(ctx, level)
+}
+
+// log writes a log record when logging is enabled at the given level
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if !c.logEnabled(ctx, level) {
+		return
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logRequest dumps an outgoing HTTP request at debug level
+func (c *Client) logRequest(ctx context.Context, req *http.Request, body []byte) {
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "deepseek: sending request",
+		slog.String("method", req.Method),
+		slog.String("url", req.URL.String()),
+		slog.Any("headers", c.redactHeaders(req.Header)),
+		slog.String("body", c.redactPayload(body)),
+	)
+}
+
+// logResponse dumps a received response payload at debug level
+func (c *Client) logResponse(ctx context.Context, resp *http.Response, body []byte) {
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "deepseek: received response",
+		slog.Int("status", resp.StatusCode),
+		slog.Any("headers", c.redactHeaders(resp.Header)),
+		slog.String("body", c.redactPayload(body)),
+	)
+}
+
+// logCall writes a summary of a completed call
+func (c *Client) logCall(ctx context.Context, req *Request, state *callState, resp *Response, err error, elapsed time.Duration) {
+	level := slog.LevelInfo
+	msg := "deepseek: request completed"
+	if err != nil {
+		level = slog.LevelError
+		msg = "deepseek: request failed"
+	}
+	if !c.logEnabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.Path),
+		slog.Bool("stream", req.Stream),
+		slog.Int("attempts", state.attempts),
+		slog.Duration("duration", elapsed),
+	}
+	if model := requestModel(req.Body); model != "" {
+		attrs = append(attrs, slog.String("model", model))
+	}
+	if state.baseURL != "" && state.baseURL != c.baseURL {
+		attrs = append(attrs, slog.String("base_url", state.baseURL))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if usage, ok := responseUsage(resp.Body); ok {
+			attrs = append(attrs,
+				slog.Int("prompt_tokens", usage.PromptTokens),
+				slog.Int("completion_tokens", usage.CompletionTokens),
+				slog.Int("total_tokens", usage.TotalTokens),
+			)
+		}
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// logRetry records a retry of a call
+func (c *Client) logRetry(ctx context.Context, req *Request, state *callState, delay time.Duration, err error) {
+	c.log(ctx, slog.LevelWarn, "deepseek: retrying request",
+		slog.String("method", req.Method),
+		slog.String("path", req.Path),
+		slog.Int("attempt", state.attempts),
+		slog.Duration("delay", delay),
+		slog.String("error", err.Error()),
+	)
+}
+
+// redactHeaders returns a copy of h with the API key and the configured headers redacted
+func (c *Client) redactHeaders(h http.Header) http.Header {
+	redacted := h.Clone()
+	names := append([]string{c.authHeaderName()}, c.redaction.Headers...)
+	for _, name := range names {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// redactPayload returns body for logging, with contents masked when configured
+func (c *Client) redactPayload(body []byte) string {
+	if !c.redaction.MaskContent || len(body) == 0 {
+		return strings.TrimSpace(string(body))
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return redactedValue
+	}
+	masked, err := json.Marshal(maskContent(v))
+	if err != nil {
+		return redactedValue
+	}
+	return string(masked)
+}
+
+// maskContent replaces the values of content fields in a decoded JSON value
+func maskContent(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for key, field := range val {
+			if s, ok := field.(string); ok && maskedFields[key] {
+				val[key] = fmt.Sprintf("[REDACTED %d chars]", len(s))
+				continue
+			}
+			val[key] = maskContent(field)
+		}
+	case []interface{}:
+		for i := range val {
+			val[i] = maskContent(val[i])
+		}
+	}
+	return v
+}
+
+// requestModel returns the model of a call, if any
+func requestModel(body interface{}) string {
+	switch req := body.(type) {
+	case *ChatCompletionRequest:
+		return req.Model
+	case *CompletionRequest:
+		return req.Model
+	default:
+		return ""
+	}
+}
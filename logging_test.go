@@ -0,0 +1,134 @@
+package deepseek_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func newTestLogger(level slog.Level) (*slog.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	return slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: level})), &buf
+}
+
+func TestLoggingSummariesAndRetries(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"overloaded"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"chat-1","choices":[{"message":{"content":"hi"}}],"usage":{"total_tokens":12}}`))
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	client, err := deepseek.NewClient("sk-secret-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+		deepseek.WithLogger(logger),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "top secret prompt"}},
+	})
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "deepseek: retrying request")
+	assert.Contains(t, out, "deepseek: request completed")
+	assert.Contains(t, out, "attempts=2")
+	assert.Contains(t, out, "model=deepseek-chat")
+	assert.Contains(t, out, "total_tokens=12")
+	assert.NotContains(t, out, "top secret prompt", "payloads are only logged at debug level")
+}
+
+func TestLoggingDebugPayloadsRedacted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"id":"chat-1","choices":[{"message":{"content":"secret answer"}}]}`))
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelDebug)
+	client, err := deepseek.NewClient("sk-secret-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithLogger(logger),
+		deepseek.WithLogRedaction(deepseek.LogRedaction{Headers: []string{"X-Tenant"}, MaskContent: true}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "top secret prompt"}},
+	}, deepseek.WithRequestHeader("X-Tenant", "tenant-secret"))
+	require.NoError(t, err)
+
+	out := buf.String()
+	assert.Contains(t, out, "deepseek: sending request")
+	assert.Contains(t, out, "deepseek: received response")
+	assert.Contains(t, out, "[REDACTED]")
+	assert.Contains(t, out, "REDACTED 17 chars")
+	assert.NotContains(t, out, "sk-secret-key")
+	assert.NotContains(t, out, "tenant-secret")
+	assert.NotContains(t, out, "top secret prompt")
+	assert.NotContains(t, out, "secret answer")
+}
+
+func TestLoggingStreamLifecycle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"Hel\"}}]}\n\n" +
+			"data: {\"choices\":[{\"delta\":{\"content\":\"lo\"}}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithLogger(logger),
+	)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	require.NoError(t, err)
+	content, err := deepseek.CollectFullResponse(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", content)
+
+	out := buf.String()
+	assert.Contains(t, out, "deepseek: stream opened")
+	assert.Contains(t, out, "deepseek: stream completed")
+	assert.Contains(t, out, "chunks=2")
+}
+
+func TestLoggingErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	logger, buf := newTestLogger(slog.LevelInfo)
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithLogger(logger),
+	)
+	require.NoError(t, err)
+
+	_, err = client.GetBalance(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, buf.String(), "level=ERROR")
+	assert.Contains(t, buf.String(), "deepseek: request failed")
+}
@@ -0,0 +1,127 @@
+package deepseek
+
+import (
+	"context"
+	"time"
+)
+
+// RequestMetrics describes a completed call
+type RequestMetrics struct {
+	// Endpoint is the API path, e.g. "/chat/completions"
+	Endpoint string
+	// Model is the requested model, empty for calls without one
+	Model string
+	// Stream reports whether the call opened a stream. Its Duration is the time until the stream was opened.
+	Stream bool
+	// StatusCode is the HTTP status of the last attempt, 0 when no response was received
+	StatusCode int
+	// Duration is the total latency of the call, retries included
+	Duration time.Duration
+	// Attempts is the number of HTTP requests sent
+	Attempts int
+	// ErrorType classifies the error of a failed call, empty on success
+	ErrorType string
+	// Usage is the token usage reported by the API
+	Usage Usage
+	// TokensPerSecond is the completion token throughput of the call
+	TokensPerSecond float64
+}
+
+// StreamMetrics describes a finished stream
+type StreamMetrics struct {
+	// Endpoint is the API path, e.g. "/chat/completions"
+	Endpoint string
+	// Model is the requested model
+	Model string
+	// TimeToFirstToken is the time from opening the stream to the first chunk
+	TimeToFirstToken time.Duration
+	// Duration is the time from opening the stream to its end
+	Duration time.Duration
+	// Chunks is the number of chunks received
+	Chunks int
+	// CompletionTokens is the number of completion tokens, estimated from the chunks
+	// when the API does not report usage
+	CompletionTokens int
+	// TokensPerSecond is the completion token throughput after the first token
+	TokensPerSecond float64
+	// Usage is the token usage reported by the API, for streams requested with IncludeUsage
+	Usage Usage
+	// ErrorType classifies the error that ended the stream, empty on success
+	ErrorType string
+}
+
+// MetricsCollector receives measurements from the client. Implementations must be
+// safe for concurrent use.
+type MetricsCollector interface {
+	// RecordRequest is called once per completed call
+	RecordRequest(m RequestMetrics)
+	// RecordRetry is called before each retry of a call
+	RecordRetry(endpoint, model, errorType string)
+	// RecordChunkLatency is called for every stream chunk after the first with the time since the previous chunk
+	RecordChunkLatency(endpoint, model string, latency time.Duration)
+	// RecordStream is called once per finished stream
+	RecordStream(m StreamMetrics)
+}
+
+// WithMetrics sets the collector that receives latency, retry, error and throughput measurements
+func WithMetrics(collector MetricsCollector) ClientOption {
+	return func(c *Client) {
+		c.metrics = collector
+	}
+}
+
+// recordRequest reports a completed call to the metrics collector
+func (c *Client) recordRequest(req *Request, state *callState, resp *Response, err error, elapsed time.Duration) {
+	if c.metrics == nil {
+		return
+	}
+
+	m := RequestMetrics{
+		Endpoint: req.Path,
+		Model:    requestModel(req.Body),
+		Stream:   req.Stream,
+		Duration: elapsed,
+		Attempts: state.attempts,
+	}
+	if err != nil {
+		m.ErrorType = errorType(err)
+	}
+	if state.statusCode != 0 {
+		m.StatusCode = state.statusCode
+	}
+	if resp != nil {
+		m.StatusCode = resp.StatusCode
+		if usage, ok := responseUsage(resp.Body); ok {
+			m.Usage = usage
+			m.TokensPerSecond = tokensPerSecond(usage.CompletionTokens, elapsed)
+		}
+	}
+	c.metrics.RecordRequest(m)
+}
+
+// recordRetry reports a retry to the metrics collector
+func (c *Client) recordRetry(req *Request, err error) {
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.RecordRetry(req.Path, requestModel(req.Body), errorType(err))
+}
+
+// tokensPerSecond returns the throughput of tokens generated over d
+func tokensPerSecond(tokens int, d time.Duration) float64 {
+	if tokens <= 0 || d <= 0 {
+		return 0
+	}
+	return float64(tokens) / d.Seconds()
+}
+
+// streamErrorType classifies the error that ended a stream
+func streamErrorType(ctx context.Context, err error) string {
+	if err == nil {
+		return ""
+	}
+	if ctx != nil && ctx.Err() != nil {
+		return errorType(ctx.Err())
+	}
+	return errorType(err)
+}
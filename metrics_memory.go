@@ -0,0 +1,386 @@
+package deepseek
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the histogram upper bounds in seconds used by InMemoryMetrics
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// InMemoryMetrics is a MetricsCollector that aggregates measurements in memory.
+// It needs no external dependencies and suits tests and simple dashboards.
+type InMemoryMetrics struct {
+	mu       sync.Mutex
+	buckets  []float64
+	requests map[metricsKey]*requestSeries
+	streams  map[metricsKey]*streamSeries
+	errors   map[string]int64
+}
+
+// metricsKey identifies a series
+type metricsKey struct {
+	endpoint string
+	model    string
+}
+
+type requestSeries struct {
+	count            int64
+	errors           int64
+	retries          int64
+	promptTokens     int64
+	completionTokens int64
+	latency          *histogram
+	tokensPerSecond  *histogram
+}
+
+type streamSeries struct {
+	count            int64
+	errors           int64
+	promptTokens     int64
+	completionTokens int64
+	timeToFirstToken *histogram
+	chunkLatency     *histogram
+	tokensPerSecond  *histogram
+}
+
+// MetricsSnapshot is a point-in-time copy of the measurements of an InMemoryMetrics
+type MetricsSnapshot struct {
+	Requests []RequestSeries `json:"requests"`
+	Streams  []StreamSeries  `json:"streams"`
+	// Errors counts failed calls and streams by error type
+	Errors map[string]int64 `json:"errors"`
+}
+
+// RequestSeries aggregates the calls of an endpoint and model
+type RequestSeries struct {
+	Endpoint         string            `json:"endpoint"`
+	Model            string            `json:"model,omitempty"`
+	Count            int64             `json:"count"`
+	Errors           int64             `json:"errors"`
+	Retries          int64             `json:"retries"`
+	PromptTokens     int64             `json:"prompt_tokens"`
+	CompletionTokens int64             `json:"completion_tokens"`
+	Latency          HistogramSnapshot `json:"latency_seconds"`
+	TokensPerSecond  HistogramSnapshot `json:"tokens_per_second"`
+}
+
+// StreamSeries aggregates the streams of an endpoint and model
+type StreamSeries struct {
+	Endpoint         string            `json:"endpoint"`
+	Model            string            `json:"model,omitempty"`
+	Count            int64             `json:"count"`
+	Errors           int64             `json:"errors"`
+	PromptTokens     int64             `json:"prompt_tokens"`
+	CompletionTokens int64             `json:"completion_tokens"`
+	TimeToFirstToken HistogramSnapshot `json:"time_to_first_token_seconds"`
+	ChunkLatency     HistogramSnapshot `json:"chunk_latency_seconds"`
+	TokensPerSecond  HistogramSnapshot `json:"tokens_per_second"`
+}
+
+// HistogramSnapshot is a copy of a histogram. Latencies are in seconds.
+type HistogramSnapshot struct {
+	Count   int64             `json:"count"`
+	Sum     float64           `json:"sum"`
+	Min     float64           `json:"min"`
+	Max     float64           `json:"max"`
+	Buckets []HistogramBucket `json:"buckets,omitempty"`
+}
+
+// HistogramBucket counts the observations less than or equal to UpperBound
+type HistogramBucket struct {
+	UpperBound float64 `json:"le"`
+	Count      int64   `json:"count"`
+}
+
+// Mean returns the average of the observations
+func (h HistogramSnapshot) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / float64(h.Count)
+}
+
+// NewInMemoryMetrics creates an InMemoryMetrics. The latency histograms use the
+// given upper bounds in seconds, or DefaultLatencyBuckets when none are given.
+func NewInMemoryMetrics(buckets ...float64) *InMemoryMetrics {
+	if len(buckets) == 0 {
+		buckets = DefaultLatencyBuckets
+	}
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+
+	return &InMemoryMetrics{
+		buckets:  sorted,
+		requests: make(map[metricsKey]*requestSeries),
+		streams:  make(map[metricsKey]*streamSeries),
+		errors:   make(map[string]int64),
+	}
+}
+
+func (m *InMemoryMetrics) request(endpoint, model string) *requestSeries {
+	key := metricsKey{endpoint, model}
+	s, ok := m.requests[key]
+	if !ok {
+		s = &requestSeries{
+			latency:         newHistogram(m.buckets),
+			tokensPerSecond: newHistogram(nil),
+		}
+		m.requests[key] = s
+	}
+	return s
+}
+
+func (m *InMemoryMetrics) stream(endpoint, model string) *streamSeries {
+	key := metricsKey{endpoint, model}
+	s, ok := m.streams[key]
+	if !ok {
+		s = &streamSeries{
+			timeToFirstToken: newHistogram(m.buckets),
+			chunkLatency:     newHistogram(m.buckets),
+			tokensPerSecond:  newHistogram(nil),
+		}
+		m.streams[key] = s
+	}
+	return s
+}
+
+// RecordRequest implements MetricsCollector
+func (m *InMemoryMetrics) RecordRequest(r RequestMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.request(r.Endpoint, r.Model)
+	s.count++
+	s.latency.observe(r.Duration.Seconds())
+	s.promptTokens += int64(r.Usage.PromptTokens)
+	s.completionTokens += int64(r.Usage.CompletionTokens)
+	if r.TokensPerSecond > 0 {
+		s.tokensPerSecond.observe(r.TokensPerSecond)
+	}
+	if r.ErrorType != "" {
+		s.errors++
+		m.errors[r.ErrorType]++
+	}
+}
+
+// RecordRetry implements MetricsCollector
+func (m *InMemoryMetrics) RecordRetry(endpoint, model, _ string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.request(endpoint, model).retries++
+}
+
+// RecordChunkLatency implements MetricsCollector
+func (m *InMemoryMetrics) RecordChunkLatency(endpoint, model string, latency time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stream(endpoint, model).chunkLatency.observe(latency.Seconds())
+}
+
+// RecordStream implements MetricsCollector
+func (m *InMemoryMetrics) RecordStream(r StreamMetrics) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stream(r.Endpoint, r.Model)
+	s.count++
+	s.promptTokens += int64(r.Usage.PromptTokens)
+	s.completionTokens += int64(r.CompletionTokens)
+	if r.Chunks > 0 {
+		s.timeToFirstToken.observe(r.TimeToFirstToken.Seconds())
+	}
+	if r.TokensPerSecond > 0 {
+		s.tokensPerSecond.observe(r.TokensPerSecond)
+	}
+	if r.ErrorType != "" {
+		s.errors++
+		m.errors[r.ErrorType]++
+	}
+}
+
+// Snapshot returns a copy of the current measurements, sorted by endpoint and model
+func (m *InMemoryMetrics) Snapshot() MetricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := MetricsSnapshot{Errors: make(map[string]int64, len(m.errors))}
+	for key, s := range m.requests {
+		snapshot.Requests = append(snapshot.Requests, RequestSeries{
+			Endpoint:         key.endpoint,
+			Model:            key.model,
+			Count:            s.count,
+			Errors:           s.errors,
+			Retries:          s.retries,
+			PromptTokens:     s.promptTokens,
+			CompletionTokens: s.completionTokens,
+			Latency:          s.latency.snapshot(),
+			TokensPerSecond:  s.tokensPerSecond.snapshot(),
+		})
+	}
+	for key, s := range m.streams {
+		snapshot.Streams = append(snapshot.Streams, StreamSeries{
+			Endpoint:         key.endpoint,
+			Model:            key.model,
+			Count:            s.count,
+			Errors:           s.errors,
+			PromptTokens:     s.promptTokens,
+			CompletionTokens: s.completionTokens,
+			TimeToFirstToken: s.timeToFirstToken.snapshot(),
+			ChunkLatency:     s.chunkLatency.snapshot(),
+			TokensPerSecond:  s.tokensPerSecond.snapshot(),
+		})
+	}
+	for errType, count := range m.errors {
+		snapshot.Errors[errType] = count
+	}
+
+	sort.Slice(snapshot.Requests, func(i, j int) bool {
+		return seriesLess(snapshot.Requests[i].Endpoint, snapshot.Requests[i].Model,
+			snapshot.Requests[j].Endpoint, snapshot.Requests[j].Model)
+	})
+	sort.Slice(snapshot.Streams, func(i, j int) bool {
+		return seriesLess(snapshot.Streams[i].Endpoint, snapshot.Streams[i].Model,
+			snapshot.Streams[j].Endpoint, snapshot.Streams[j].Model)
+	})
+	return snapshot
+}
+
+// Reset discards all measurements
+func (m *InMemoryMetrics) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = make(map[metricsKey]*requestSeries)
+	m.streams = make(map[metricsKey]*streamSeries)
+	m.errors = make(map[string]int64)
+}
+
+// ExportJSON writes a snapshot of the measurements as JSON
+func (m *InMemoryMetrics) ExportJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m.Snapshot())
+}
+
+// ExportPrometheus writes a snapshot of the measurements in the Prometheus text exposition format
+func (m *InMemoryMetrics) ExportPrometheus(w io.Writer) error {
+	snapshot := m.Snapshot()
+	var b strings.Builder
+
+	writeHeader(&b, "deepseek_requests_total", "counter", "Completed calls")
+	for _, s := range snapshot.Requests {
+		fmt.Fprintf(&b, "deepseek_requests_total{%s} %d\n", labels(s.Endpoint, s.Model), s.Count)
+	}
+	writeHeader(&b, "deepseek_request_retries_total", "counter", "Retried attempts")
+	for _, s := range snapshot.Requests {
+		fmt.Fprintf(&b, "deepseek_request_retries_total{%s} %d\n", labels(s.Endpoint, s.Model), s.Retries)
+	}
+	writeHeader(&b, "deepseek_request_errors_total", "counter", "Failed calls and streams by error type")
+	errTypes := make([]string, 0, len(snapshot.Errors))
+	for errType := range snapshot.Errors {
+		errTypes = append(errTypes, errType)
+	}
+	sort.Strings(errTypes)
+	for _, errType := range errTypes {
+		fmt.Fprintf(&b, "deepseek_request_errors_total{type=%q} %d\n", errType, snapshot.Errors[errType])
+	}
+	writeHeader(&b, "deepseek_tokens_total", "counter", "Tokens reported by the API")
+	for _, s := range snapshot.Requests {
+		fmt.Fprintf(&b, "deepseek_tokens_total{%s,kind=\"prompt\"} %d\n", labels(s.Endpoint, s.Model), s.PromptTokens)
+		fmt.Fprintf(&b, "deepseek_tokens_total{%s,kind=\"completion\"} %d\n", labels(s.Endpoint, s.Model), s.CompletionTokens)
+	}
+	writeHeader(&b, "deepseek_stream_tokens_total", "counter", "Tokens of streams, estimated from the chunks when the API does not report usage")
+	for _, s := range snapshot.Streams {
+		fmt.Fprintf(&b, "deepseek_stream_tokens_total{%s,kind=\"prompt\"} %d\n", labels(s.Endpoint, s.Model), s.PromptTokens)
+		fmt.Fprintf(&b, "deepseek_stream_tokens_total{%s,kind=\"completion\"} %d\n", labels(s.Endpoint, s.Model), s.CompletionTokens)
+	}
+
+	writeHeader(&b, "deepseek_request_duration_seconds", "histogram", "Call latency")
+	for _, s := range snapshot.Requests {
+		writeHistogram(&b, "deepseek_request_duration_seconds", labels(s.Endpoint, s.Model), s.Latency)
+	}
+	writeHeader(&b, "deepseek_stream_time_to_first_token_seconds", "histogram", "Time from opening a stream to its first chunk")
+	for _, s := range snapshot.Streams {
+		writeHistogram(&b, "deepseek_stream_time_to_first_token_seconds", labels(s.Endpoint, s.Model), s.TimeToFirstToken)
+	}
+	writeHeader(&b, "deepseek_stream_chunk_latency_seconds", "histogram", "Time between stream chunks")
+	for _, s := range snapshot.Streams {
+		writeHistogram(&b, "deepseek_stream_chunk_latency_seconds", labels(s.Endpoint, s.Model), s.ChunkLatency)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func writeHistogram(b *strings.Builder, name, labels string, h HistogramSnapshot) {
+	for _, bucket := range h.Buckets {
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"%g\"} %d\n", name, labels, bucket.UpperBound, bucket.Count)
+	}
+	fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.Count)
+	fmt.Fprintf(b, "%s_sum{%s} %g\n", name, labels, h.Sum)
+	fmt.Fprintf(b, "%s_count{%s} %d\n", name, labels, h.Count)
+}
+
+func labels(endpoint, model string) string {
+	return fmt.Sprintf("endpoint=%q,model=%q", endpoint, model)
+}
+
+func seriesLess(endpointA, modelA, endpointB, modelB string) bool {
+	if endpointA != endpointB {
+		return endpointA < endpointB
+	}
+	return modelA < modelB
+}
+
+// histogram counts observations into cumulative buckets
+type histogram struct {
+	bounds []float64
+	counts []int64
+	count  int64
+	sum    float64
+	min    float64
+	max    float64
+}
+
+func newHistogram(bounds []float64) *histogram {
+	return &histogram{
+		bounds: bounds,
+		counts: make([]int64, len(bounds)),
+		min:    math.Inf(1),
+		max:    math.Inf(-1),
+	}
+}
+
+func (h *histogram) observe(v float64) {
+	h.count++
+	h.sum += v
+	h.min = math.Min(h.min, v)
+	h.max = math.Max(h.max, v)
+	for i, bound := range h.bounds {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+}
+
+func (h *histogram) snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{Count: h.count, Sum: h.sum}
+	if h.count > 0 {
+		s.Min = h.min
+		s.Max = h.max
+	}
+	for i, bound := range h.bounds {
+		s.Buckets = append(s.Buckets, HistogramBucket{UpperBound: bound, Count: h.counts[i]})
+	}
+	return s
+}
@@ -0,0 +1,143 @@
+package deepseek_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestInMemoryMetricsRequests(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/balance":
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"invalid api key"}`))
+		default:
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"message":"overloaded"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"id":"chat-1","choices":[{"message":{"content":"hi"}}],` +
+				`"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`))
+		}
+	}))
+	defer server.Close()
+
+	metrics := deepseek.NewInMemoryMetrics()
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+		deepseek.WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	require.NoError(t, err)
+	_, err = client.GetBalance(context.Background())
+	require.Error(t, err)
+
+	snapshot := metrics.Snapshot()
+	require.Len(t, snapshot.Requests, 2)
+
+	chat := snapshot.Requests[0]
+	assert.Equal(t, "/chat/completions", chat.Endpoint)
+	assert.Equal(t, "deepseek-chat", chat.Model)
+	assert.Equal(t, int64(1), chat.Count)
+	assert.Equal(t, int64(1), chat.Retries)
+	assert.Equal(t, int64(0), chat.Errors)
+	assert.Equal(t, int64(3), chat.PromptTokens)
+	assert.Equal(t, int64(4), chat.CompletionTokens)
+	assert.Equal(t, int64(1), chat.Latency.Count)
+	assert.Greater(t, chat.Latency.Sum, 0.0)
+	assert.Equal(t, int64(1), chat.TokensPerSecond.Count)
+
+	balance := snapshot.Requests[1]
+	assert.Equal(t, "/user/balance", balance.Endpoint)
+	assert.Equal(t, int64(1), balance.Errors)
+	assert.Equal(t, map[string]int64{"authentication": 1}, snapshot.Errors)
+}
+
+func TestInMemoryMetricsStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		for _, chunk := range []string{"Hel", "lo", "!"} {
+			_, _ = w.Write([]byte("data: {\"choices\":[{\"delta\":{\"content\":\"" + chunk + "\"}}]}\n\n"))
+			flusher.Flush()
+			time.Sleep(5 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	metrics := deepseek.NewInMemoryMetrics()
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMetrics(metrics),
+	)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	require.NoError(t, err)
+	_, err = deepseek.CollectFullResponse(stream)
+	require.NoError(t, err)
+
+	snapshot := metrics.Snapshot()
+	require.Len(t, snapshot.Streams, 1)
+	s := snapshot.Streams[0]
+	assert.Equal(t, int64(1), s.Count)
+	assert.Equal(t, int64(3), s.CompletionTokens)
+	assert.Equal(t, int64(1), s.TimeToFirstToken.Count)
+	assert.Equal(t, int64(2), s.ChunkLatency.Count)
+	assert.GreaterOrEqual(t, s.ChunkLatency.Min, 0.004)
+	assert.Equal(t, int64(1), s.TokensPerSecond.Count)
+}
+
+func TestInMemoryMetricsExport(t *testing.T) {
+	metrics := deepseek.NewInMemoryMetrics(0.1, 1)
+	metrics.RecordRequest(deepseek.RequestMetrics{
+		Endpoint: "/chat/completions",
+		Model:    "deepseek-chat",
+		Duration: 500 * time.Millisecond,
+	})
+	metrics.RecordRequest(deepseek.RequestMetrics{
+		Endpoint:  "/chat/completions",
+		Model:     "deepseek-chat",
+		Duration:  2 * time.Second,
+		ErrorType: "rate_limit",
+	})
+
+	var promBuf bytes.Buffer
+	require.NoError(t, metrics.ExportPrometheus(&promBuf))
+	prom := promBuf.String()
+	assert.Contains(t, prom, `deepseek_requests_total{endpoint="/chat/completions",model="deepseek-chat"} 2`)
+	assert.Contains(t, prom, `deepseek_request_duration_seconds_bucket{endpoint="/chat/completions",model="deepseek-chat",le="0.1"} 0`)
+	assert.Contains(t, prom, `deepseek_request_duration_seconds_bucket{endpoint="/chat/completions",model="deepseek-chat",le="1"} 1`)
+	assert.Contains(t, prom, `deepseek_request_duration_seconds_bucket{endpoint="/chat/completions",model="deepseek-chat",le="+Inf"} 2`)
+	assert.Contains(t, prom, `deepseek_request_errors_total{type="rate_limit"} 1`)
+
+	var jsonBuf bytes.Buffer
+	require.NoError(t, metrics.ExportJSON(&jsonBuf))
+	var snapshot deepseek.MetricsSnapshot
+	require.NoError(t, json.Unmarshal(jsonBuf.Bytes(), &snapshot))
+	require.Len(t, snapshot.Requests, 1)
+	assert.Equal(t, int64(2), snapshot.Requests[0].Latency.Count)
+	assert.InDelta(t, 1.25, snapshot.Requests[0].Latency.Mean(), 0.001)
+
+	metrics.Reset()
+	assert.Empty(t, metrics.Snapshot().Requests)
+}
@@ -0,0 +1,91 @@
+package deepseek
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+)
+
+// Request describes an outgoing API call as seen by middleware
+type Request struct {
+	// Method is the HTTP method of the call
+	Method string
+	// Path is the API path relative to the base URL, e.g. "/chat/completions"
+	Path string
+	// Body is the decoded request payload, e.g. *ChatCompletionRequest. It is nil for GET calls.
+	Body interface{}
+	// Header holds extra headers applied to the HTTP request, overriding the defaults
+	Header http.Header
+	// Stream reports whether the call expects a streaming response
+	Stream bool
+
+	// result is the value the response body is decoded into
+	result interface{}
+	// options holds the per-call overrides of client settings
+	options requestOptions
+}
+
+// Response describes the result of an API call as seen by middleware
+type Response struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Header holds the HTTP response headers
+	Header http.Header
+	// Body is the decoded response payload, e.g. *ChatCompletionResponse. It is nil for streams.
+	Body interface{}
+	// Stream is the open stream for streaming calls
+	Stream *Stream
+	// HTTPResponse is the underlying HTTP response. For non-streaming calls its body has already been consumed.
+	// A middleware that short-circuits a streaming call may return an HTTPResponse carrying an
+	// event-stream body instead of a Stream.
+	HTTPResponse *http.Response
+}
+
+// Handler executes an API call
+type Handler func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps a Handler to inspect or modify requests and responses.
+// A middleware may short-circuit a call by returning a Response without calling next.
+type Middleware func(next Handler) Handler
+
+// WithMiddleware adds middleware to the client. Middleware is applied in the order given,
+// the first one being the outermost.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// chainMiddleware wraps h with the given middleware, the first one being the outermost
+func chainMiddleware(h Handler, middleware []Middleware) Handler {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		h = middleware[i](h)
+	}
+	return h
+}
+
+// assignResult stores the response body returned by the handler chain in v.
+// This is a no-op when the core handler decoded directly into v.
+func assignResult(v interface{}, body interface{}) error {
+	if v == nil || body == nil || body == v {
+		return nil
+	}
+
+	dst := reflect.ValueOf(v)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("cannot assign response to %T", v)
+	}
+	dst = dst.Elem()
+
+	src := reflect.ValueOf(body)
+	if src.Kind() == reflect.Ptr && !src.IsNil() && src.Elem().Type() == dst.Type() {
+		src = src.Elem()
+	}
+	if src.Type() != dst.Type() {
+		return fmt.Errorf("middleware returned %T, want %T", body, v)
+	}
+
+	dst.Set(src)
+	return nil
+}
@@ -0,0 +1,134 @@
+package deepseek_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestMiddlewareSeesTypedRequestAndResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer rewritten", r.Header.Get("Authorization"))
+		assert.Equal(t, "audit-1", r.Header.Get("X-Audit-ID"))
+		_, _ = w.Write([]byte(`{"id":"chat-1","choices":[{"message":{"role":"assistant","content":"hi"}}]}`))
+	}))
+	defer server.Close()
+
+	var order []string
+	var seenModel, seenID string
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMiddleware(
+			func(next deepseek.Handler) deepseek.Handler {
+				return func(ctx context.Context, req *deepseek.Request) (*deepseek.Response, error) {
+					order = append(order, "outer")
+					if req.Header == nil {
+						req.Header = http.Header{}
+					}
+					req.Header.Set("Authorization", "Bearer rewritten")
+					return next(ctx, req)
+				}
+			},
+			func(next deepseek.Handler) deepseek.Handler {
+				return func(ctx context.Context, req *deepseek.Request) (*deepseek.Response, error) {
+					order = append(order, "inner")
+					req.Header.Set("X-Audit-ID", "audit-1")
+					if chatReq, ok := req.Body.(*deepseek.ChatCompletionRequest); ok {
+						seenModel = chatReq.Model
+					}
+					resp, err := next(ctx, req)
+					if err == nil {
+						if chatResp, ok := resp.Body.(*deepseek.ChatCompletionResponse); ok {
+							seenID = chatResp.ID
+						}
+					}
+					return resp, err
+				}
+			},
+		),
+	)
+	require.NoError(t, err)
+
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "chat-1", resp.ID)
+	assert.Equal(t, []string{"outer", "inner"}, order)
+	assert.Equal(t, "deepseek-chat", seenModel)
+	assert.Equal(t, "chat-1", seenID)
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+
+	cache := func(next deepseek.Handler) deepseek.Handler {
+		return func(ctx context.Context, req *deepseek.Request) (*deepseek.Response, error) {
+			if req.Stream {
+				body := "data: {\"choices\":[{\"delta\":{\"content\":\"cached\"}}]}\n\ndata: [DONE]\n\n"
+				return &deepseek.Response{
+					StatusCode: http.StatusOK,
+					HTTPResponse: &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(body)),
+					},
+				}, nil
+			}
+			return &deepseek.Response{
+				StatusCode: http.StatusOK,
+				Body: &deepseek.ChatCompletionResponse{
+					ID:      "cached",
+					Choices: []deepseek.Choice{{Message: deepseek.Message{Content: "cached"}}},
+				},
+			}, nil
+		}
+	}
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMiddleware(cache),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	}
+
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", resp.ID)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), req)
+	require.NoError(t, err)
+	content, err := deepseek.CollectFullResponse(stream)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", content)
+
+	assert.Equal(t, int32(0), atomic.LoadInt32(&hits))
+}
+
+func TestMiddlewareWrongResponseType(t *testing.T) {
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithMiddleware(func(next deepseek.Handler) deepseek.Handler {
+			return func(ctx context.Context, req *deepseek.Request) (*deepseek.Response, error) {
+				return &deepseek.Response{Body: &deepseek.Balance{}}, nil
+			}
+		}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ListModels(context.Background())
+	assert.ErrorContains(t, err, "middleware returned")
+}
@@ -22,7 +22,7 @@ type ModelList struct {
 }
 
 // ListModels lists all available models
-func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
+func (c *Client) ListModels(ctx context.Context, opts ...RequestOption) (*ModelList, error) {
 	if ctx == nil {
 		return nil, &errors.InvalidRequestError{
 			Param: "context",
@@ -30,10 +30,7 @@ func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
 		}
 	}
 
-	req, err := c.newRequest(ctx, http.MethodGet, "/models", nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+	req := newCall(http.MethodGet, "/models", nil, opts)
 
 	var models ModelList
 	if err := c.do(ctx, req, &models); err != nil {
@@ -44,7 +41,7 @@ func (c *Client) ListModels(ctx context.Context) (*ModelList, error) {
 }
 
 // GetModel retrieves information about a specific model
-func (c *Client) GetModel(ctx context.Context, modelID string) (*Model, error) {
+func (c *Client) GetModel(ctx context.Context, modelID string, opts ...RequestOption) (*Model, error) {
 	if ctx == nil {
 		return nil, &errors.InvalidRequestError{
 			Param: "context",
@@ -59,10 +56,7 @@ func (c *Client) GetModel(ctx context.Context, modelID string) (*Model, error) {
 		}
 	}
 
-	req, err := c.newRequest(ctx, http.MethodGet, "/models/"+modelID, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+	req := newCall(http.MethodGet, "/models/"+modelID, nil, opts)
 
 	var model Model
 	if err := c.do(ctx, req, &model); err != nil {
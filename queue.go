@@ -0,0 +1,256 @@
+package deepseek
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Priority orders calls waiting in the request queue. Higher priorities are
+// admitted first; any int value can be used.
+type Priority int
+
+const (
+	// PriorityBatch is for background jobs that can wait
+	PriorityBatch Priority = -1
+	// PriorityNormal is the priority of calls without one in their context
+	PriorityNormal Priority = 0
+	// PriorityInteractive is for calls a user is waiting on
+	PriorityInteractive Priority = 1
+)
+
+type priorityKey struct{}
+
+type tenantKey struct{}
+
+// ContextWithPriority returns a context that queues calls made with it at priority p
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// ContextWithTenant returns a context that attributes calls made with it to tenant.
+// Tenants waiting at the same priority share the queue fairly.
+func ContextWithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// contextPriority returns the priority carried by ctx
+func contextPriority(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityNormal
+}
+
+// contextTenant returns the tenant carried by ctx
+func contextTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// QueueStats describes the state of the request queue
+type QueueStats struct {
+	// MaxInFlight is the number of in-flight requests allowed
+	MaxInFlight int
+	// InFlight is the number of requests whose response has not been read yet,
+	// open streams included
+	InFlight int
+	// Depth is the number of requests waiting for a slot
+	Depth int
+	// DepthByPriority is the number of waiting requests per priority
+	DepthByPriority map[Priority]int
+	// Admitted counts the requests that were given a slot
+	Admitted int64
+	// Expired counts the requests whose context ended while they were waiting
+	Expired int64
+	// AverageWait is the mean time admitted requests spent waiting
+	AverageWait time.Duration
+	// MaxWait is the longest time an admitted request spent waiting
+	MaxWait time.Duration
+}
+
+// WithMaxInFlight limits the number of in-flight requests. Excess requests wait
+// in a queue ordered by the priority set with ContextWithPriority; within a
+// priority, the tenant set with ContextWithTenant that has the fewest requests
+// in flight goes first. Waiting requests give up when their context ends.
+func WithMaxInFlight(limit int) ClientOption {
+	return func(c *Client) {
+		if limit > 0 {
+			c.queue = newRequestQueue(limit)
+		}
+	}
+}
+
+// QueueStats returns the state of the request queue. It returns the zero value
+// when the queue is not enabled.
+func (c *Client) QueueStats() QueueStats {
+	if c.queue == nil {
+		return QueueStats{}
+	}
+	return c.queue.stats()
+}
+
+// requestQueue admits requests up to a limit and queues the rest by priority and tenant
+type requestQueue struct {
+	mu             sync.Mutex
+	limit          int
+	inFlight       int
+	tenantInFlight map[string]int
+	waiters        []*queueWaiter
+	seq            uint64
+	admitted       int64
+	expired        int64
+	totalWait      time.Duration
+	maxWait        time.Duration
+}
+
+// queueWaiter is a request waiting for a slot
+type queueWaiter struct {
+	priority Priority
+	tenant   string
+	seq      uint64
+	enqueued time.Time
+	ready    chan struct{}
+	admitted bool
+}
+
+func newRequestQueue(limit int) *requestQueue {
+	return &requestQueue{
+		limit:          limit,
+		tenantInFlight: make(map[string]int),
+	}
+}
+
+// acquire blocks until the request is given a slot or ctx is done. The returned
+// function frees the slot.
+func (q *requestQueue) acquire(ctx context.Context) (func(), error) {
+	tenant := contextTenant(ctx)
+	release := func() { q.release(tenant) }
+
+	q.mu.Lock()
+	if q.inFlight < q.limit && len(q.waiters) == 0 {
+		q.admit(tenant, 0)
+		q.mu.Unlock()
+		return release, nil
+	}
+
+	q.seq++
+	w := &queueWaiter{
+		priority: contextPriority(ctx),
+		tenant:   tenant,
+		seq:      q.seq,
+		enqueued: time.Now(),
+		ready:    make(chan struct{}),
+	}
+	q.waiters = append(q.waiters, w)
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if w.admitted {
+		// Admitted while the context ended; hand the slot on
+		q.releaseLocked(tenant)
+	} else {
+		q.remove(w)
+	}
+	q.expired++
+	return nil, ctx.Err()
+}
+
+// release frees the slot of a request of tenant
+func (q *requestQueue) release(tenant string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.releaseLocked(tenant)
+}
+
+// releaseLocked frees a slot with q.mu held
+func (q *requestQueue) releaseLocked(tenant string) {
+	q.inFlight--
+	q.tenantInFlight[tenant]--
+	if q.tenantInFlight[tenant] <= 0 {
+		delete(q.tenantInFlight, tenant)
+	}
+	q.dispatch()
+}
+
+// admit gives a slot to a request of tenant
+func (q *requestQueue) admit(tenant string, wait time.Duration) {
+	q.inFlight++
+	q.tenantInFlight[tenant]++
+	q.admitted++
+	q.totalWait += wait
+	if wait > q.maxWait {
+		q.maxWait = wait
+	}
+}
+
+// dispatch admits waiting requests while slots are free
+func (q *requestQueue) dispatch() {
+	for q.inFlight < q.limit && len(q.waiters) > 0 {
+		w := q.next()
+		q.remove(w)
+		q.admit(w.tenant, time.Since(w.enqueued))
+		w.admitted = true
+		close(w.ready)
+	}
+}
+
+// next picks the waiter to admit: highest priority first, then the tenant with
+// the fewest requests in flight, then the longest waiting
+func (q *requestQueue) next() *queueWaiter {
+	best := q.waiters[0]
+	for _, w := range q.waiters[1:] {
+		switch {
+		case w.priority != best.priority:
+			if w.priority > best.priority {
+				best = w
+			}
+		case q.tenantInFlight[w.tenant] != q.tenantInFlight[best.tenant]:
+			if q.tenantInFlight[w.tenant] < q.tenantInFlight[best.tenant] {
+				best = w
+			}
+		case w.seq < best.seq:
+			best = w
+		}
+	}
+	return best
+}
+
+// remove takes w out of the waiters
+func (q *requestQueue) remove(w *queueWaiter) {
+	for i, other := range q.waiters {
+		if other == w {
+			q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *requestQueue) stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	s := QueueStats{
+		MaxInFlight:     q.limit,
+		InFlight:        q.inFlight,
+		Depth:           len(q.waiters),
+		DepthByPriority: make(map[Priority]int),
+		Admitted:        q.admitted,
+		Expired:         q.expired,
+		MaxWait:         q.maxWait,
+	}
+	for _, w := range q.waiters {
+		s.DepthByPriority[w.priority]++
+	}
+	if q.admitted > 0 {
+		s.AverageWait = q.totalWait / time.Duration(q.admitted)
+	}
+	return s
+}
@@ -0,0 +1,195 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+// blockingServer records the X-Call header of every request in order of arrival
+// and holds the request until release is closed
+type blockingServer struct {
+	*httptest.Server
+	release chan struct{}
+	mu      sync.Mutex
+	order   []string
+}
+
+func newBlockingServer(t *testing.T) *blockingServer {
+	s := &blockingServer{release: make(chan struct{})}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.order = append(s.order, r.Header.Get("X-Call"))
+		s.mu.Unlock()
+		<-s.release
+		_, _ = w.Write([]byte(`{"is_available":true}`))
+	}))
+	t.Cleanup(s.Server.Close)
+	return s
+}
+
+func (s *blockingServer) arrived() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.order...)
+}
+
+func TestQueuePriorityOrder(t *testing.T) {
+	server := newBlockingServer(t)
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxInFlight(1),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	call := func(ctx context.Context, name string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.GetBalance(ctx, deepseek.WithRequestHeader("X-Call", name))
+			assert.NoError(t, err)
+		}()
+	}
+
+	call(context.Background(), "first")
+	require.Eventually(t, func() bool { return client.QueueStats().InFlight == 1 }, time.Second, time.Millisecond)
+
+	call(deepseek.ContextWithPriority(context.Background(), deepseek.PriorityBatch), "batch")
+	require.Eventually(t, func() bool { return client.QueueStats().Depth == 1 }, time.Second, time.Millisecond)
+	call(deepseek.ContextWithPriority(context.Background(), deepseek.PriorityInteractive), "interactive")
+	require.Eventually(t, func() bool { return client.QueueStats().Depth == 2 }, time.Second, time.Millisecond)
+
+	stats := client.QueueStats()
+	assert.Equal(t, 1, stats.DepthByPriority[deepseek.PriorityBatch])
+	assert.Equal(t, 1, stats.DepthByPriority[deepseek.PriorityInteractive])
+
+	close(server.release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"first", "interactive", "batch"}, server.arrived())
+	stats = client.QueueStats()
+	assert.Equal(t, int64(3), stats.Admitted)
+	assert.Equal(t, 0, stats.Depth)
+	assert.Greater(t, stats.MaxWait, time.Duration(0))
+}
+
+func TestQueueTenantFairness(t *testing.T) {
+	server := newBlockingServer(t)
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxInFlight(2),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	call := func(tenant, name string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := deepseek.ContextWithTenant(context.Background(), tenant)
+			_, err := client.GetBalance(ctx, deepseek.WithRequestHeader("X-Call", name))
+			assert.NoError(t, err)
+		}()
+	}
+
+	// Tenant a fills both slots and queues more work before tenant b arrives
+	for i, name := range []string{"a1", "a2", "a3", "a4"} {
+		call("a", name)
+		want := i + 1
+		require.Eventually(t, func() bool {
+			s := client.QueueStats()
+			return s.InFlight+s.Depth == want
+		}, time.Second, time.Millisecond)
+	}
+	call("b", "b1")
+	require.Eventually(t, func() bool { return client.QueueStats().Depth == 3 }, time.Second, time.Millisecond)
+
+	// Free one slot: b has nothing in flight and goes ahead of a's backlog
+	server.release <- struct{}{}
+	require.Eventually(t, func() bool { return len(server.arrived()) == 3 }, time.Second, time.Millisecond)
+	assert.Equal(t, "b1", server.arrived()[2])
+
+	close(server.release)
+	wg.Wait()
+	assert.Len(t, server.arrived(), 5)
+}
+
+func TestQueueRespectsDeadline(t *testing.T) {
+	server := newBlockingServer(t)
+	defer close(server.release)
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxInFlight(1),
+	)
+	require.NoError(t, err)
+
+	go func() { _, _ = client.GetBalance(context.Background()) }()
+	require.Eventually(t, func() bool { return client.QueueStats().InFlight == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.GetBalance(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	stats := client.QueueStats()
+	assert.Equal(t, int64(1), stats.Expired)
+	assert.Equal(t, 0, stats.Depth)
+}
+
+func TestQueueHoldsSlotUntilStreamEnds(t *testing.T) {
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"choices":[{"index":0,"delta":{"content":"hi"}}]}` + "\n\n"))
+		w.(http.Flusher).Flush()
+		time.Sleep(20 * time.Millisecond)
+		// Leave before the client can see the end of the stream
+		atomic.AddInt32(&active, -1)
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxInFlight(1),
+	)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+				Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+			})
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer stream.Close()
+			content, err := deepseek.CollectFullResponse(stream)
+			assert.NoError(t, err)
+			assert.Equal(t, "hi", content)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&peak))
+	assert.Equal(t, 0, client.QueueStats().InFlight)
+}
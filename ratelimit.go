@@ -0,0 +1,216 @@
+package deepseek
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit configures client-side request and token budgets.
+// A zero value for either field disables that budget.
+type RateLimit struct {
+	// RequestsPerMinute limits the number of HTTP requests sent per minute,
+	// including retries and failovers
+	RequestsPerMinute int
+	// TokensPerMinute limits the number of tokens consumed per minute
+	TokensPerMinute int
+}
+
+// WithRateLimit enables client-side rate limiting. The limit applies to every model
+// without a limit of its own; each model gets its own budget.
+func WithRateLimit(limit RateLimit) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter().defaultLimit = limit
+	}
+}
+
+// WithModelRateLimit sets the rate limit for a specific model, overriding the limit set by WithRateLimit
+func WithModelRateLimit(model string, limit RateLimit) ClientOption {
+	return func(c *Client) {
+		c.rateLimiter().models[model] = limit
+	}
+}
+
+// rateLimiter enforces RPM and TPM budgets using one pair of token buckets per model
+type rateLimiter struct {
+	mu           sync.Mutex
+	defaultLimit RateLimit
+	models       map[string]RateLimit
+	buckets      map[string]*rateBuckets
+}
+
+// rateBuckets holds the request and token buckets of a single model
+type rateBuckets struct {
+	requests *tokenBucket
+	tokens   *tokenBucket
+}
+
+// tokenBucket refills continuously up to its per-minute capacity
+type tokenBucket struct {
+	capacity  float64
+	available float64
+	perSecond float64
+	last      time.Time
+}
+
+// rateReservation records the tokens taken for a call so they can be corrected later
+type rateReservation struct {
+	limiter *rateLimiter
+	buckets *rateBuckets
+	tokens  int
+}
+
+// rateLimiter returns the client's rate limiter, creating it on first use
+func (c *Client) rateLimiter() *rateLimiter {
+	if c.limiter == nil {
+		c.limiter = &rateLimiter{
+			models:  make(map[string]RateLimit),
+			buckets: make(map[string]*rateBuckets),
+		}
+	}
+	return c.limiter
+}
+
+func newTokenBucket(perMinute int, now time.Time) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &tokenBucket{
+		capacity:  float64(perMinute),
+		available: float64(perMinute),
+		perSecond: float64(perMinute) / 60,
+		last:      now,
+	}
+}
+
+// refill adds the tokens accrued since the last refill
+func (b *tokenBucket) refill(now time.Time) {
+	if b == nil {
+		return
+	}
+	b.available = math.Min(b.capacity, b.available+now.Sub(b.last).Seconds()*b.perSecond)
+	b.last = now
+}
+
+// delay returns how long until n tokens are available
+func (b *tokenBucket) delay(n float64) time.Duration {
+	if b == nil || b.available >= n {
+		return 0
+	}
+	return time.Duration((n - b.available) / b.perSecond * float64(time.Second))
+}
+
+// take removes n tokens from the bucket. The balance may go negative when correcting
+// an underestimate.
+func (b *tokenBucket) take(n float64) {
+	if b == nil {
+		return
+	}
+	b.available = math.Min(b.capacity, b.available-n)
+}
+
+// bucketsFor returns the buckets of a model, or nil when the model is not limited
+func (l *rateLimiter) bucketsFor(model string, now time.Time) *rateBuckets {
+	if b, ok := l.buckets[model]; ok {
+		return b
+	}
+
+	limit, ok := l.models[model]
+	if !ok {
+		limit = l.defaultLimit
+	}
+
+	var b *rateBuckets
+	if limit.RequestsPerMinute > 0 || limit.TokensPerMinute > 0 {
+		b = &rateBuckets{
+			requests: newTokenBucket(limit.RequestsPerMinute, now),
+			tokens:   newTokenBucket(limit.TokensPerMinute, now),
+		}
+	}
+	l.buckets[model] = b
+	return b
+}
+
+// wait blocks until the model's budget allows a call costing the estimated tokens,
+// or until ctx is done
+func (l *rateLimiter) wait(ctx context.Context, model string, tokens int) (*rateReservation, error) {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		b := l.bucketsFor(model, now)
+		if b == nil {
+			l.mu.Unlock()
+			return nil, nil
+		}
+
+		b.requests.refill(now)
+		b.tokens.refill(now)
+
+		// Never ask for more than a bucket can hold, or the call would wait forever
+		cost := float64(tokens)
+		if b.tokens != nil {
+			cost = math.Min(cost, b.tokens.capacity)
+		}
+
+		d := b.requests.delay(1)
+		if td := b.tokens.delay(cost); td > d {
+			d = td
+		}
+		if d == 0 {
+			b.requests.take(1)
+			b.tokens.take(cost)
+			l.mu.Unlock()
+			return &rateReservation{limiter: l, buckets: b, tokens: int(cost)}, nil
+		}
+		l.mu.Unlock()
+
+		if err := sleepContext(ctx, d); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// waitRequest blocks until the model's budget allows another HTTP request of a
+// call already admitted by wait, or until ctx is done. Retries and failovers
+// spend no tokens of their own; the call's usage is reconciled once.
+func (l *rateLimiter) waitRequest(ctx context.Context, model string) error {
+	_, err := l.wait(ctx, model, 0)
+	return err
+}
+
+// reconcile corrects the token budget using the tokens actually consumed
+func (r *rateReservation) reconcile(actual int) {
+	if r == nil || r.buckets.tokens == nil {
+		return
+	}
+
+	r.limiter.mu.Lock()
+	defer r.limiter.mu.Unlock()
+	r.buckets.tokens.take(float64(actual - r.tokens))
+	r.tokens = actual
+}
+
+// requestCost returns the model and estimated token cost of a call
+func (c *Client) requestCost(body interface{}) (string, int) {
+	switch req := body.(type) {
+	case *ChatCompletionRequest:
+		return req.Model, c.EstimateTokensFromMessages(req.Messages).EstimatedTokens + req.MaxTokens
+	case *CompletionRequest:
+		return req.Model, c.EstimateTokenCount(req.Prompt).EstimatedTokens + req.MaxTokens
+	default:
+		return "", 0
+	}
+}
+
+// responseUsage returns the token usage reported in a decoded response
+func responseUsage(body interface{}) (Usage, bool) {
+	switch resp := body.(type) {
+	case *ChatCompletionResponse:
+		return resp.Usage, true
+	case *CompletionResponse:
+		return resp.Usage, true
+	default:
+		return Usage{}, false
+	}
+}
@@ -0,0 +1,120 @@
+package deepseek_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func newUsageServer(t *testing.T, totalTokens int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"id":"chat-1","choices":[{"message":{"role":"assistant","content":"hi"}}],`+
+			`"usage":{"total_tokens":%d}}`, totalTokens)
+	}))
+}
+
+func TestRateLimitRequestsPerMinute(t *testing.T) {
+	server := newUsageServer(t, 10)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRateLimit(deepseek.RateLimit{RequestsPerMinute: 1}),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	}
+
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateChatCompletion(ctx, req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRateLimitTokensCorrectedByUsage(t *testing.T) {
+	server := newUsageServer(t, 10)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRateLimit(deepseek.RateLimit{TokensPerMinute: 1000}),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages:  []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+		MaxTokens: 600,
+	}
+
+	// Both calls are estimated at more than half of the budget, so the second
+	// one only fits once the first has been corrected by its reported usage.
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err = client.CreateChatCompletion(ctx, req)
+		cancel()
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimitPerModel(t *testing.T) {
+	server := newUsageServer(t, 10)
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRateLimit(deepseek.RateLimit{RequestsPerMinute: 1}),
+		deepseek.WithModelRateLimit("deepseek-reasoner", deepseek.RateLimit{RequestsPerMinute: 100}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		_, err = client.CreateChatCompletion(ctx, &deepseek.ChatCompletionRequest{
+			Model:    "deepseek-reasoner",
+			Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+		})
+		cancel()
+		require.NoError(t, err)
+	}
+}
+
+func TestRateLimitCountsRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(3),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+		deepseek.WithRateLimit(deepseek.RateLimit{RequestsPerMinute: 1}),
+	)
+	require.NoError(t, err)
+
+	// The retry of the only call allowed this minute must wait for the budget too
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = client.CreateChatCompletion(ctx, &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, int32(1), requests.Load())
+}
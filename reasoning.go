@@ -0,0 +1,96 @@
+package deepseek
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Models served by the DeepSeek API
+const (
+	ModelChat     = "deepseek-chat"
+	ModelReasoner = "deepseek-reasoner"
+)
+
+// isReasoner reports whether model is deepseek-reasoner or a version of it
+func isReasoner(model string) bool {
+	return strings.HasPrefix(model, ModelReasoner)
+}
+
+// ValidationWarning describes a request parameter that will not behave as
+// the caller probably expects
+type ValidationWarning struct {
+	Param   string
+	Message string
+}
+
+// String returns the warning as "param: message"
+func (w ValidationWarning) String() string {
+	return w.Param + ": " + w.Message
+}
+
+// Warnings returns the parameters of the request that its model ignores or
+// rejects. Requests to deepseek-reasoner are checked for sampling parameters,
+// which have no effect, and for function calling and JSON output, which it
+// does not support.
+func (r *ChatCompletionRequest) Warnings() []ValidationWarning {
+	if !isReasoner(r.Model) {
+		return nil
+	}
+
+	var warnings []ValidationWarning
+	ignored := func(param string, set bool) {
+		if set {
+			warnings = append(warnings, ValidationWarning{param, "is ignored by " + ModelReasoner})
+		}
+	}
+	unsupported := func(param string, set bool) {
+		if set {
+			warnings = append(warnings, ValidationWarning{param, "is not supported by " + ModelReasoner + " and may be rejected"})
+		}
+	}
+
+	ignored("temperature", r.Temperature != 0)
+	ignored("top_p", r.TopP != 0)
+	ignored("presence_penalty", r.PresencePenalty != 0)
+	ignored("frequency_penalty", r.FrequencyPenalty != 0)
+	ignored("logit_bias", len(r.LogitBias) > 0)
+	unsupported("tools", len(r.Tools) > 0 || len(r.Functions) > 0)
+	unsupported("tool_choice", r.ToolChoice != nil || r.FunctionCall != "")
+	unsupported("response_format", r.JSONMode || (r.ResponseFormat != nil && r.ResponseFormat.Type == "json_object"))
+	return warnings
+}
+
+// warnRequest logs the warnings of a request
+func (c *Client) warnRequest(ctx context.Context, req *ChatCompletionRequest) {
+	if !c.logEnabled(ctx, slog.LevelWarn) {
+		return
+	}
+	for _, w := range req.Warnings() {
+		c.log(ctx, slog.LevelWarn, "deepseek: request parameter "+w.Message,
+			slog.String("param", w.Param), slog.String("model", req.Model))
+	}
+}
+
+// StripReasoning returns the messages without their reasoning content, which
+// the API rejects in input messages. messages is not modified.
+func StripReasoning(messages []Message) []Message {
+	if !hasReasoning(messages) {
+		return messages
+	}
+	stripped := make([]Message, len(messages))
+	for i, msg := range messages {
+		msg.ReasoningContent = ""
+		stripped[i] = msg
+	}
+	return stripped
+}
+
+func hasReasoning(messages []Message) bool {
+	for _, msg := range messages {
+		if msg.ReasoningContent != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+package deepseek_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestReasoningContent(t *testing.T) {
+	var body map[string]any
+	server := toolServer(t, `{"choices":[{"message":{"role":"assistant","reasoning_content":"9.11 < 9.8","content":"9.8 is greater"}}]}`, &body)
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Model:    deepseek.ModelReasoner,
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "9.11 or 9.8?"}},
+	}
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	msg := resp.Choices[0].Message
+	assert.Equal(t, "9.11 < 9.8", msg.ReasoningContent)
+	assert.Equal(t, "9.8 is greater", msg.Content)
+
+	// The reasoning content is not sent back with the conversation
+	req.Messages = append(req.Messages, msg, deepseek.Message{Role: deepseek.RoleUser, Content: "Why?"})
+	_, err = client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+	sent := body["messages"].([]any)
+	require.Len(t, sent, 3)
+	assert.NotContains(t, sent[1], "reasoning_content")
+	assert.Equal(t, "9.8 is greater", sent[1].(map[string]any)["content"])
+	assert.Equal(t, "9.11 < 9.8", req.Messages[1].ReasoningContent, "the caller's messages are not modified")
+}
+
+func TestStripReasoning(t *testing.T) {
+	messages := []deepseek.Message{
+		{Role: deepseek.RoleUser, Content: "hi"},
+		{Role: deepseek.RoleAssistant, Content: "hello", ReasoningContent: "greet back"},
+	}
+	stripped := deepseek.StripReasoning(messages)
+	assert.Empty(t, stripped[1].ReasoningContent)
+	assert.Equal(t, "hello", stripped[1].Content)
+	assert.Equal(t, "greet back", messages[1].ReasoningContent)
+}
+
+func TestStreamReasoningContent(t *testing.T) {
+	server := sseServer(t,
+		`{"choices":[{"index":0,"delta":{"role":"assistant","reasoning_content":"Compare "}}]}`,
+		`{"choices":[{"index":0,"delta":{"reasoning_content":"decimals."}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"9.8"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":" is greater"},"finish_reason":"stop"}]}`,
+	)
+
+	stream := openStream(t, server)
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "Compare ", chunk.Choices[0].Delta.ReasoningContent)
+
+	var acc deepseek.MessageAccumulator
+	acc.Add(chunk.Choices[0].Delta)
+	for {
+		chunk, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		acc.Add(chunk.Choices[0].Delta)
+	}
+	assert.Equal(t, "Compare decimals.", acc.Reasoning())
+	assert.Equal(t, "9.8 is greater", acc.Content())
+
+	msg, err := deepseek.CollectMessage(openStream(t, server))
+	require.NoError(t, err)
+	assert.Equal(t, "Compare decimals.", msg.ReasoningContent)
+	assert.Equal(t, "9.8 is greater", msg.Content)
+}
+
+func TestReasonerWarnings(t *testing.T) {
+	req := &deepseek.ChatCompletionRequest{
+		Model:       deepseek.ModelReasoner,
+		Temperature: 0.7,
+		Tools:       []deepseek.Tool{{Type: deepseek.ToolTypeFunction, Function: &weatherFunction}},
+		JSONMode:    true,
+	}
+	var params []string
+	for _, w := range req.Warnings() {
+		params = append(params, w.Param)
+	}
+	assert.Equal(t, []string{"temperature", "tools", "response_format"}, params)
+	assert.Equal(t, "temperature: is ignored by deepseek-reasoner", req.Warnings()[0].String())
+
+	req.Model = deepseek.ModelChat
+	assert.Empty(t, req.Warnings())
+}
+
+func TestReasonerWarningsLogged(t *testing.T) {
+	var body map[string]any
+	server := toolServer(t, `{"choices":[]}`, &body)
+	logger, buf := newTestLogger(slog.LevelWarn)
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL), deepseek.WithLogger(logger))
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Model:    deepseek.ModelReasoner,
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+		TopP:     0.9,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "deepseek: request parameter is ignored by deepseek-reasoner")
+	assert.Contains(t, buf.String(), "param=top_p")
+	assert.Equal(t, 0.9, body["top_p"], "the parameter is still sent")
+}
@@ -0,0 +1,102 @@
+package deepseek
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption overrides client settings for a single call
+type RequestOption func(*Request)
+
+// requestOptions holds the per-call overrides of client settings
+type requestOptions struct {
+	apiKey        string
+	baseURL       string
+	maxRetries    *int
+	retryWaitTime time.Duration
+	timeout       time.Duration
+	meta          *ResponseMeta
+}
+
+// WithRequestAPIKey sends the call with the given API key instead of the client key or key pool
+func WithRequestAPIKey(apiKey string) RequestOption {
+	return func(r *Request) {
+		r.options.apiKey = apiKey
+	}
+}
+
+// WithRequestBaseURL sends the call to the given base URL instead of the client endpoints
+func WithRequestBaseURL(url string) RequestOption {
+	return func(r *Request) {
+		r.options.baseURL = url
+	}
+}
+
+// WithRequestHeader sets an extra header on the call
+func WithRequestHeader(key, value string) RequestOption {
+	return func(r *Request) {
+		if r.Header == nil {
+			r.Header = make(http.Header)
+		}
+		r.Header.Set(key, value)
+	}
+}
+
+// WithRequestMaxRetries sets the maximum number of retries for the call
+func WithRequestMaxRetries(retries int) RequestOption {
+	return func(r *Request) {
+		r.options.maxRetries = &retries
+	}
+}
+
+// WithRequestRetryWaitTime sets the base wait time between retries of the call
+func WithRequestRetryWaitTime(duration time.Duration) RequestOption {
+	return func(r *Request) {
+		r.options.retryWaitTime = duration
+	}
+}
+
+// WithRequestTimeout bounds the call, retries included. For streams the timeout
+// covers reading the whole stream.
+func WithRequestTimeout(timeout time.Duration) RequestOption {
+	return func(r *Request) {
+		r.options.timeout = timeout
+	}
+}
+
+// newCall creates the Request of a call and applies its options
+func newCall(method, path string, body interface{}, opts []RequestOption) *Request {
+	r := &Request{Method: method, Path: path, Body: body}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// withTimeout applies the per-call timeout to ctx
+func (r *Request) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.options.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, r.options.timeout)
+}
+
+// maxRetriesFor returns the retry limit of the call
+func (c *Client) maxRetriesFor(r *Request) int {
+	if r.options.maxRetries != nil {
+		return *r.options.maxRetries
+	}
+	if !c.enableRetries {
+		return 0
+	}
+	return c.maxRetries
+}
+
+// retryWaitTimeFor returns the base wait time between retries of the call
+func (c *Client) retryWaitTimeFor(r *Request) time.Duration {
+	if r.options.retryWaitTime > 0 {
+		return r.options.retryWaitTime
+	}
+	return c.retryWaitTime
+}
@@ -0,0 +1,119 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestRequestOptionsOverrideKeyHeadersAndBaseURL(t *testing.T) {
+	defaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request sent to the client base URL")
+	}))
+	defer defaultServer.Close()
+
+	var auth, tenant string
+	tenantServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		tenant = r.Header.Get("X-Tenant")
+		_, _ = w.Write([]byte(`{"is_available":true}`))
+	}))
+	defer tenantServer.Close()
+
+	client, err := deepseek.NewClient("client-key", deepseek.WithBaseURL(defaultServer.URL))
+	require.NoError(t, err)
+
+	balance, err := client.GetBalance(context.Background(),
+		deepseek.WithRequestAPIKey("tenant-key"),
+		deepseek.WithRequestBaseURL(tenantServer.URL),
+		deepseek.WithRequestHeader("X-Tenant", "acme"),
+	)
+	require.NoError(t, err)
+	assert.True(t, balance.IsAvailable)
+	assert.Equal(t, "Bearer tenant-key", auth)
+	assert.Equal(t, "acme", tenant)
+}
+
+func TestRequestOptionsOverrideRetries(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"unavailable"}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithMaxRetries(5),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ListModels(context.Background(), deepseek.WithRequestMaxRetries(0))
+	require.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+	atomic.StoreInt32(&hits, 0)
+	_, err = client.ListModels(context.Background(),
+		deepseek.WithRequestMaxRetries(2),
+		deepseek.WithRequestRetryWaitTime(time.Millisecond),
+	)
+	require.Error(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+}
+
+func TestRequestOptionsTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL), deepseek.WithMaxRetries(0))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	}, deepseek.WithRequestTimeout(50*time.Millisecond))
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRequestOptionsTimeoutCoversStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	}, deepseek.WithRequestTimeout(100*time.Millisecond))
+	require.NoError(t, err)
+	defer stream.Close()
+
+	chunk, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "hi", chunk.Choices[0].Delta.Content)
+
+	_, err = stream.Recv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package deepseek
+
+import (
+	"net/http"
+	"time"
+)
+
+// requestIDHeaders are the response headers that carry the server request ID
+var requestIDHeaders = []string{"X-Request-Id", "Request-Id", "X-Trace-Id"}
+
+// ResponseMeta holds HTTP-level information about a call
+type ResponseMeta struct {
+	// StatusCode is the status of the last response received
+	StatusCode int
+	// Header holds the headers of the last response received
+	Header http.Header
+	// RequestID is the request ID assigned by the server, if any
+	RequestID string
+	// Latency is the total time of the call including retries. For streams it
+	// is the time until the response headers arrived.
+	Latency time.Duration
+	// Attempts is the number of HTTP requests sent
+	Attempts int
+	// Endpoint is the base URL used by the last attempt
+	Endpoint string
+}
+
+// WithResponseMeta fills meta with the HTTP-level information of the call once
+// it completes. It is filled for failed calls too, as long as a request was sent.
+func WithResponseMeta(meta *ResponseMeta) RequestOption {
+	return func(r *Request) {
+		r.options.meta = meta
+	}
+}
+
+// fillResponseMeta copies what happened during a call into the caller's ResponseMeta
+func fillResponseMeta(meta *ResponseMeta, state *callState, elapsed time.Duration) {
+	*meta = ResponseMeta{
+		StatusCode: state.statusCode,
+		Header:     state.header,
+		Latency:    elapsed,
+		Attempts:   state.attempts,
+		Endpoint:   state.baseURL,
+	}
+	for _, name := range requestIDHeaders {
+		if id := state.header.Get(name); id != "" {
+			meta.RequestID = id
+			break
+		}
+	}
+}
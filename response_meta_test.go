@@ -0,0 +1,64 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestResponseMeta(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = w.Write([]byte(`{"message":"busy"}`))
+			return
+		}
+		w.Header().Set("X-Request-Id", "req-123")
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	var meta deepseek.ResponseMeta
+	_, err = client.ListModels(context.Background(), deepseek.WithResponseMeta(&meta))
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, meta.StatusCode)
+	assert.Equal(t, "req-123", meta.RequestID)
+	assert.Equal(t, "req-123", meta.Header.Get("X-Request-Id"))
+	assert.Equal(t, 2, meta.Attempts)
+	assert.Equal(t, server.URL, meta.Endpoint)
+	assert.Greater(t, meta.Latency, time.Duration(0))
+}
+
+func TestResponseMetaOnError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-401")
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid key"}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var meta deepseek.ResponseMeta
+	_, err = client.GetBalance(context.Background(), deepseek.WithResponseMeta(&meta))
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, meta.StatusCode)
+	assert.Equal(t, "req-401", meta.RequestID)
+	assert.Equal(t, 1, meta.Attempts)
+}
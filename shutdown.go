@@ -0,0 +1,151 @@
+package deepseek
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClientClosed is returned for calls made after Close or Shutdown
+var ErrClientClosed = errors.New("deepseek: client is closed")
+
+// ActiveCalls is the number of calls in progress
+type ActiveCalls struct {
+	// Requests is the number of non-streaming calls awaiting a response
+	Requests int
+	// Streams is the number of streams that have not finished or been closed
+	Streams int
+}
+
+// ActiveCalls returns the number of calls in progress
+func (c *Client) ActiveCalls() ActiveCalls {
+	return c.active.count()
+}
+
+// Shutdown stops the client gracefully. New calls are refused with
+// ErrClientClosed while in-flight requests and open streams are left to finish.
+// If ctx ends first, the remaining calls are cancelled and ctx.Err() is returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	drained := c.active.close()
+	c.stopBackground()
+
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		c.active.cancelAll()
+		return ctx.Err()
+	}
+}
+
+// Close stops the client immediately. New calls are refused with
+// ErrClientClosed, and in-flight requests and open streams are cancelled.
+func (c *Client) Close() error {
+	c.active.close()
+	c.active.cancelAll()
+	c.stopBackground()
+	return nil
+}
+
+// stopBackground stops the background health checks
+func (c *Client) stopBackground() {
+	if c.stopHealthChecks != nil {
+		c.stopHealthChecks()
+	}
+}
+
+// activeCalls tracks the calls in progress so they can be drained or cancelled
+type activeCalls struct {
+	mu      sync.Mutex
+	closed  bool
+	next    uint64
+	calls   map[uint64]activeCall
+	drained chan struct{}
+}
+
+// activeCall is a call in progress
+type activeCall struct {
+	cancel context.CancelFunc
+	stream bool
+}
+
+// begin registers a call. The returned context is cancelled when the client is
+// closed, and the returned function must be called once the call is over.
+func (a *activeCalls) begin(ctx context.Context, stream bool) (context.Context, func(), error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.closed {
+		return nil, nil, ErrClientClosed
+	}
+	if a.calls == nil {
+		a.calls = make(map[uint64]activeCall)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	a.next++
+	id := a.next
+	a.calls[id] = activeCall{cancel: cancel, stream: stream}
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			cancel()
+			a.end(id)
+		})
+	}, nil
+}
+
+// end unregisters a call
+func (a *activeCalls) end(id uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.calls, id)
+	if a.closed && len(a.calls) == 0 && a.drained != nil {
+		close(a.drained)
+		a.drained = nil
+	}
+}
+
+// close refuses new calls and returns a channel closed once no call is in progress
+func (a *activeCalls) close() <-chan struct{} {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.closed = true
+	drained := make(chan struct{})
+	if len(a.calls) == 0 {
+		close(drained)
+		return drained
+	}
+	if a.drained == nil {
+		a.drained = make(chan struct{})
+	}
+	return a.drained
+}
+
+// cancelAll cancels every call in progress
+func (a *activeCalls) cancelAll() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, call := range a.calls {
+		call.cancel()
+	}
+}
+
+func (a *activeCalls) count() ActiveCalls {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var n ActiveCalls
+	for _, call := range a.calls {
+		if call.stream {
+			n.Streams++
+		} else {
+			n.Requests++
+		}
+	}
+	return n
+}
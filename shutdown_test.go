@@ -0,0 +1,96 @@
+package deepseek_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = w.Write([]byte(`{"is_available":true}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.GetBalance(context.Background())
+		result <- err
+	}()
+	require.Eventually(t, func() bool { return client.ActiveCalls().Requests == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, client.Shutdown(ctx))
+	assert.NoError(t, <-result)
+	assert.Equal(t, deepseek.ActiveCalls{}, client.ActiveCalls())
+
+	_, err = client.GetBalance(context.Background())
+	assert.True(t, errors.Is(err, deepseek.ErrClientClosed))
+}
+
+func TestShutdownCancelsAfterDeadline(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.ListModels(context.Background())
+		result <- err
+	}()
+	require.Eventually(t, func() bool { return client.ActiveCalls().Requests == 1 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, client.Shutdown(ctx), context.DeadlineExceeded)
+	assert.ErrorIs(t, <-result, context.Canceled)
+}
+
+func TestCloseCancelsStreams(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"hi\"}}]}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	defer stream.Close()
+
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 1, client.ActiveCalls().Streams)
+
+	require.NoError(t, client.Close())
+	_, err = stream.Recv()
+	assert.Error(t, err)
+	assert.Equal(t, 0, client.ActiveCalls().Streams)
+
+	_, err = client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	assert.ErrorIs(t, err, deepseek.ErrClientClosed)
+}
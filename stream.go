@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/trustsight-io/deepseek-go/internal/errors"
 )
@@ -20,15 +22,53 @@ type Stream struct {
 	errChan   chan error
 	done      bool
 	closeOnce chan struct{}
+
+	// Lifecycle bookkeeping, set when the stream is opened by a client
+	client        *Client
+	ctx           context.Context
+	request       *Request
+	state         *callState
+	started       time.Time
+	firstChunk    time.Time
+	lastChunk     time.Time
+	chunks        int
+	contentChunks int
+	id            string
+	model         string
+	finishReasons []string
+	ended         bool
+	release       func()
+	slots         *slotBody
+	think         map[int]*thinkParser
+	usage         *Usage
+	reservation   *rateReservation
 }
 
 // StreamChoice represents a choice in a streaming response
 type StreamChoice struct {
-	Delta struct {
-		Content string `json:"content,omitempty"`
-		Role    string `json:"role,omitempty"`
-	} `json:"delta"`
-	FinishReason string `json:"finish_reason,omitempty"`
+	Index        int         `json:"index"`
+	Delta        StreamDelta `json:"delta"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+}
+
+// StreamDelta is the part of a message carried by a streamed chunk
+type StreamDelta struct {
+	Content string `json:"content,omitempty"`
+	Role    string `json:"role,omitempty"`
+	// ReasoningContent holds a piece of the chain of thought of deepseek-reasoner
+	ReasoningContent string `json:"reasoning_content,omitempty"`
+	// ToolCalls holds fragments of tool calls; use ToolCallAccumulator to assemble them
+	ToolCalls []ToolCallDelta `json:"tool_calls,omitempty"`
+}
+
+// ToolCallDelta is a fragment of a streamed tool call. The first fragment of a
+// call carries its ID and function name; the arguments arrive in pieces.
+type ToolCallDelta struct {
+	// Index identifies the call the fragment belongs to
+	Index    int              `json:"index"`
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"`
+	Function ToolCallFunction `json:"function"`
 }
 
 // StreamResponse represents a streamed response chunk
@@ -38,6 +78,8 @@ type StreamResponse struct {
 	Created int64          `json:"created"`
 	Model   string         `json:"model"`
 	Choices []StreamChoice `json:"choices"`
+	// Usage is set on the final chunk of streams requested with IncludeUsage
+	Usage *Usage `json:"usage,omitempty"`
 }
 
 // newStream creates a new Stream from an HTTP response
@@ -50,6 +92,162 @@ func newStream(resp *http.Response) *Stream {
 	}
 }
 
+// openStream creates a Stream for a call and logs that it was opened
+func (c *Client) openStream(ctx context.Context, req *Request, resp *http.Response, state *callState) *Stream {
+	s := newStream(resp)
+	s.client = c
+	s.ctx = ctx
+	s.request = req
+	s.state = state
+	s.started = time.Now()
+	s.slots, _ = resp.Body.(*slotBody)
+
+	c.log(ctx, slog.LevelInfo, "deepseek: stream opened", s.logAttrs()...)
+	return s
+}
+
+// logAttrs returns the attributes describing the stream in log records
+func (s *Stream) logAttrs() []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String("path", s.request.Path),
+		slog.Int("chunks", s.chunks),
+		slog.Duration("duration", time.Since(s.started)),
+	}
+	if model := requestModel(s.request.Body); model != "" {
+		attrs = append(attrs, slog.String("model", model))
+	}
+	if s.usage != nil {
+		attrs = append(attrs,
+			slog.Int("prompt_tokens", s.usage.PromptTokens),
+			slog.Int("completion_tokens", s.usage.CompletionTokens),
+			slog.Int("total_tokens", s.usage.TotalTokens),
+		)
+	}
+	return attrs
+}
+
+// finish logs how the stream ended and ends its span
+func (s *Stream) finish(err error) {
+	if s.client == nil || s.ended {
+		return
+	}
+	s.ended = true
+	s.recordUsage()
+	s.endSpan(err)
+	s.recordMetrics(err)
+	s.slots.done()
+	if s.release != nil {
+		defer s.release()
+	}
+
+	if err != nil {
+		s.client.log(s.ctx, slog.LevelError, "deepseek: stream failed",
+			append(s.logAttrs(), slog.String("error", err.Error()))...)
+		return
+	}
+	s.client.log(s.ctx, slog.LevelInfo, "deepseek: stream completed", s.logAttrs()...)
+}
+
+// observe records a received chunk
+func (s *Stream) observe(chunk *StreamResponse) {
+	now := time.Now()
+	s.chunks++
+	if s.chunks > 1 && s.client != nil && s.client.metrics != nil {
+		s.client.metrics.RecordChunkLatency(s.request.Path, requestModel(s.request.Body), now.Sub(s.lastChunk))
+	}
+	s.lastChunk = now
+	if s.chunks == 1 {
+		s.firstChunk = now
+		if s.state != nil {
+			ttft := s.firstChunk.Sub(s.started)
+			s.state.span.AddEvent(EventFirstToken, Attribute{AttrStreamTimeToFirstToken, ttft.Seconds()})
+			s.state.span.SetAttributes(Attribute{AttrStreamTimeToFirstToken, ttft.Seconds()})
+		}
+	}
+	if chunk.ID != "" {
+		s.id = chunk.ID
+	}
+	if chunk.Model != "" {
+		s.model = chunk.Model
+	}
+	if chunk.Usage != nil {
+		usage := *chunk.Usage
+		s.usage = &usage
+	}
+	for _, choice := range chunk.Choices {
+		if choice.Delta.Content != "" || choice.Delta.ReasoningContent != "" || len(choice.Delta.ToolCalls) > 0 {
+			s.contentChunks++
+		}
+		if choice.FinishReason != "" {
+			s.finishReasons = append(s.finishReasons, choice.FinishReason)
+		}
+	}
+}
+
+// Usage returns the token usage reported at the end of a stream requested
+// with IncludeUsage, or nil if none was received yet
+func (s *Stream) Usage() *Usage {
+	if s.usage == nil {
+		return nil
+	}
+	usage := *s.usage
+	return &usage
+}
+
+// recordUsage corrects the rate limiter's estimate and the usage of the key
+// with the usage reported by the stream
+func (s *Stream) recordUsage() {
+	if s.usage == nil || s.usage.TotalTokens == 0 {
+		return
+	}
+	s.reservation.reconcile(s.usage.TotalTokens)
+	if s.client.keys != nil && s.state != nil {
+		s.client.keys.recordUsage(s.state.apiKey, s.usage.TotalTokens)
+	}
+}
+
+// recordMetrics reports the finished stream to the metrics collector. Unless
+// the API reported usage, each content chunk is counted as one completion token.
+func (s *Stream) recordMetrics(err error) {
+	if s.client == nil || s.client.metrics == nil {
+		return
+	}
+
+	m := StreamMetrics{
+		Endpoint:         s.request.Path,
+		Model:            requestModel(s.request.Body),
+		Duration:         time.Since(s.started),
+		Chunks:           s.chunks,
+		CompletionTokens: s.contentChunks,
+		ErrorType:        streamErrorType(s.ctx, err),
+	}
+	if s.usage != nil {
+		m.Usage = *s.usage
+		m.CompletionTokens = s.usage.CompletionTokens
+	}
+	if s.chunks > 0 {
+		m.TimeToFirstToken = s.firstChunk.Sub(s.started)
+		m.TokensPerSecond = tokensPerSecond(m.CompletionTokens, s.lastChunk.Sub(s.firstChunk))
+	}
+	s.client.metrics.RecordStream(m)
+}
+
+// endSpan sets the attributes collected from the stream and ends the span of its call
+func (s *Stream) endSpan(err error) {
+	if s.state == nil {
+		return
+	}
+	s.state.span.SetAttributes(
+		Attribute{AttrGenAIResponseID, s.id},
+		Attribute{AttrGenAIResponseModel, s.model},
+		Attribute{AttrGenAIResponseFinish, s.finishReasons},
+	)
+	if s.usage != nil {
+		s.state.span.SetAttributes(usageAttributes(*s.usage)...)
+	}
+	endSpan(s.state.span, s.state, &Response{StatusCode: s.response.StatusCode}, err)
+}
+
 // Recv receives the next chunk of data from the stream
 func (s *Stream) Recv() (*StreamResponse, error) {
 	if s.done {
@@ -60,9 +258,12 @@ func (s *Stream) Recv() (*StreamResponse, error) {
 	if err != nil {
 		if err == io.EOF {
 			s.done = true
+			s.finish(nil)
 			return nil, io.EOF
 		}
-		return nil, fmt.Errorf("error reading from stream: %w", err)
+		err = fmt.Errorf("error reading from stream: %w", err)
+		s.finish(err)
+		return nil, err
 	}
 
 	// Remove "data: " prefix
@@ -77,16 +278,28 @@ func (s *Stream) Recv() (*StreamResponse, error) {
 	// Check for stream end
 	if bytes.Equal(line, []byte("[DONE]")) {
 		s.done = true
+		s.finish(nil)
 		return nil, io.EOF
 	}
 
+	if s.client != nil && s.client.logEnabled(s.ctx, slog.LevelDebug) {
+		s.client.logger.LogAttrs(s.ctx, slog.LevelDebug, "deepseek: received stream chunk",
+			slog.String("data", s.client.redactPayload(line)))
+	}
+
 	var response StreamResponse
 	if err := json.Unmarshal(line, &response); err != nil {
-		return nil, &errors.InvalidRequestError{
+		err = &errors.InvalidRequestError{
 			Err: fmt.Errorf("failed to decode stream response: %w", err),
 		}
+		s.finish(err)
+		return nil, err
 	}
 
+	if s.client != nil && s.client.parseThinkTags {
+		s.splitThinkTags(&response)
+	}
+	s.observe(&response)
 	return &response, nil
 }
 
@@ -97,6 +310,16 @@ func (s *Stream) Close() error {
 		return nil
 	default:
 		close(s.closeOnce)
+		if s.release != nil {
+			defer s.release()
+		}
+		if !s.ended && s.client != nil {
+			s.client.log(s.ctx, slog.LevelInfo, "deepseek: stream closed before completion", s.logAttrs()...)
+			s.ended = true
+			s.recordUsage()
+			s.endSpan(nil)
+			s.recordMetrics(nil)
+		}
 		if s.response != nil && s.response.Body != nil {
 			return s.response.Body.Close()
 		}
@@ -108,6 +331,7 @@ func (s *Stream) Close() error {
 func (c *Client) CreateChatCompletionStream(
 	ctx context.Context,
 	req *ChatCompletionRequest,
+	opts ...RequestOption,
 ) (*Stream, error) {
 	if req == nil {
 		return nil, &errors.InvalidRequestError{
@@ -123,36 +347,17 @@ func (c *Client) CreateChatCompletionStream(
 		}
 	}
 
-	req.Stream = true
-	if req.Model == "" {
-		req.Model = "deepseek-chat"
-	}
-
-	httpReq, err := c.newRequest(ctx, http.MethodPost, "/chat/completions", req)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
+	if err := validateToolMessages(req.Messages); err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		defer func() {
-			if cerr := resp.Body.Close(); cerr != nil {
-				err = fmt.Errorf("error closing response body: %v", cerr)
-			}
-		}()
-		var apiErr errors.APIError
-		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err != nil {
-			return nil, fmt.Errorf("failed to decode error response: %v", err)
-		}
-		apiErr.StatusCode = resp.StatusCode
-		return nil, errors.HandleErrorResp(resp, &apiErr)
+	req.Stream = true
+	if req.Model == "" {
+		req.Model = c.chatModel()
 	}
+	c.warnRequest(ctx, req)
 
-	return newStream(resp), nil
+	return c.doStream(ctx, newCall(http.MethodPost, "/chat/completions", req.outgoing(), opts))
 }
 
 // ContentAccumulator helps accumulate streamed content
@@ -175,6 +380,138 @@ func (ca *ContentAccumulator) Reset() {
 	ca.buffer.Reset()
 }
 
+// ToolCallAccumulator assembles streamed tool call fragments into complete calls
+type ToolCallAccumulator struct {
+	calls []ToolCall
+	index map[int]int
+}
+
+// Add adds the tool call fragments of a delta
+func (ta *ToolCallAccumulator) Add(deltas []ToolCallDelta) {
+	if ta.index == nil {
+		ta.index = make(map[int]int)
+	}
+	for _, d := range deltas {
+		i, ok := ta.index[d.Index]
+		if !ok {
+			i = len(ta.calls)
+			ta.index[d.Index] = i
+			ta.calls = append(ta.calls, ToolCall{Type: ToolTypeFunction})
+		}
+		call := &ta.calls[i]
+		if d.ID != "" {
+			call.ID = d.ID
+		}
+		if d.Type != "" {
+			call.Type = d.Type
+		}
+		call.Function.Name += d.Function.Name
+		call.Function.Arguments += d.Function.Arguments
+	}
+}
+
+// ToolCalls returns the assembled calls in the order they started. Calls
+// without arguments get "{}"; an error is returned if the arguments of a call
+// are not valid JSON, e.g. because the stream was cut short.
+func (ta *ToolCallAccumulator) ToolCalls() ([]ToolCall, error) {
+	if len(ta.calls) == 0 {
+		return nil, nil
+	}
+	calls := make([]ToolCall, len(ta.calls))
+	copy(calls, ta.calls)
+	for i := range calls {
+		if strings.TrimSpace(calls[i].Function.Arguments) == "" {
+			calls[i].Function.Arguments = "{}"
+		}
+		if !json.Valid([]byte(calls[i].Function.Arguments)) {
+			return nil, fmt.Errorf("tool call %q (%s) has incomplete arguments: %s",
+				calls[i].ID, calls[i].Function.Name, calls[i].Function.Arguments)
+		}
+	}
+	return calls, nil
+}
+
+// Reset clears the accumulated calls
+func (ta *ToolCallAccumulator) Reset() {
+	ta.calls = nil
+	ta.index = nil
+}
+
+// MessageAccumulator assembles the deltas of a streamed choice into a message,
+// keeping the reasoning content apart from the answer
+type MessageAccumulator struct {
+	content   ContentAccumulator
+	reasoning ContentAccumulator
+	tools     ToolCallAccumulator
+}
+
+// Add adds a delta
+func (ma *MessageAccumulator) Add(delta StreamDelta) {
+	ma.content.Add(delta.Content)
+	ma.reasoning.Add(delta.ReasoningContent)
+	ma.tools.Add(delta.ToolCalls)
+}
+
+// Content returns the accumulated answer
+func (ma *MessageAccumulator) Content() string {
+	return ma.content.String()
+}
+
+// Reasoning returns the accumulated reasoning content
+func (ma *MessageAccumulator) Reasoning() string {
+	return ma.reasoning.String()
+}
+
+// Message returns the assistant message with its content, reasoning content
+// and complete tool calls
+func (ma *MessageAccumulator) Message() (Message, error) {
+	calls, err := ma.tools.ToolCalls()
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{
+		Role:             RoleAssistant,
+		Content:          ma.content.String(),
+		ReasoningContent: ma.reasoning.String(),
+		ToolCalls:        calls,
+	}, nil
+}
+
+// Reset clears the accumulated message
+func (ma *MessageAccumulator) Reset() {
+	ma.content.Reset()
+	ma.reasoning.Reset()
+	ma.tools.Reset()
+}
+
+// CollectMessage collects the first choice of a stream into an assistant
+// message with its content, reasoning content and complete tool calls, ready
+// to be appended to the conversation
+func CollectMessage(stream *Stream) (msg Message, err error) {
+	defer func() {
+		if cerr := stream.Close(); cerr != nil && err == nil {
+			err = fmt.Errorf("error closing stream: %v", cerr)
+		}
+	}()
+
+	var acc MessageAccumulator
+	for {
+		response, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return Message{}, err
+		}
+		for _, choice := range response.Choices {
+			if choice.Index == 0 {
+				acc.Add(choice.Delta)
+			}
+		}
+	}
+	return acc.Message()
+}
+
 // CollectFullResponse collects a complete response from a stream
 func CollectFullResponse(stream *Stream) (response string, err error) {
 	defer func() {
@@ -0,0 +1,180 @@
+package deepseek_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+// sseServer streams each chunk as a server-sent event, followed by [DONE]
+func sseServer(t *testing.T, chunks ...string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", chunk)
+		}
+		_, _ = w.Write([]byte("data: [DONE]\n\n"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func openStream(t *testing.T, server *httptest.Server, opts ...deepseek.ClientOption) *deepseek.Stream {
+	client, err := deepseek.NewClient("test-key", append([]deepseek.ClientOption{deepseek.WithBaseURL(server.URL)}, opts...)...)
+	require.NoError(t, err)
+	stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	return stream
+}
+
+func TestStreamToolCalls(t *testing.T) {
+	server := sseServer(t,
+		`{"choices":[{"index":0,"delta":{"role":"assistant","content":"Checking."}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"get_weather","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"locat"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"get_time","arguments":""}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"function":{"arguments":"ion\":\"Paris\"}"}}]}}]}`,
+		`{"choices":[{"index":0,"delta":{},"finish_reason":"tool_calls"}]}`,
+	)
+
+	stream := openStream(t, server)
+	defer stream.Close()
+	chunk, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Empty(t, chunk.Choices[0].Delta.ToolCalls)
+	chunk, err = stream.Recv()
+	require.NoError(t, err)
+	require.Len(t, chunk.Choices[0].Delta.ToolCalls, 1)
+	assert.Equal(t, "call_a", chunk.Choices[0].Delta.ToolCalls[0].ID)
+
+	msg, err := deepseek.CollectMessage(openStream(t, server))
+	require.NoError(t, err)
+	assert.Equal(t, deepseek.RoleAssistant, msg.Role)
+	assert.Equal(t, "Checking.", msg.Content)
+	assert.Equal(t, []deepseek.ToolCall{
+		{ID: "call_a", Type: "function", Function: deepseek.ToolCallFunction{Name: "get_weather", Arguments: `{"location":"Paris"}`}},
+		{ID: "call_b", Type: "function", Function: deepseek.ToolCallFunction{Name: "get_time", Arguments: "{}"}},
+	}, msg.ToolCalls)
+}
+
+func TestStreamToolCallsIncomplete(t *testing.T) {
+	server := sseServer(t,
+		`{"choices":[{"index":0,"delta":{"tool_calls":[{"index":0,"id":"call_a","function":{"name":"get_weather","arguments":"{\"loc"}}]}}]}`,
+	)
+
+	_, err := deepseek.CollectMessage(openStream(t, server))
+	assert.ErrorContains(t, err, "incomplete arguments")
+}
+
+func TestToolCallAccumulator(t *testing.T) {
+	var acc deepseek.ToolCallAccumulator
+	calls, err := acc.ToolCalls()
+	require.NoError(t, err)
+	assert.Nil(t, calls)
+
+	acc.Add([]deepseek.ToolCallDelta{{Index: 2, ID: "call_x", Function: deepseek.ToolCallFunction{Name: "lookup", Arguments: `{"q":`}}})
+	acc.Add([]deepseek.ToolCallDelta{{Index: 2, Function: deepseek.ToolCallFunction{Arguments: `"go"}`}}})
+	calls, err = acc.ToolCalls()
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "function", calls[0].Type)
+	assert.Equal(t, `{"q":"go"}`, calls[0].Function.Arguments)
+
+	acc.Reset()
+	calls, err = acc.ToolCalls()
+	require.NoError(t, err)
+	assert.Nil(t, calls)
+}
+
+func TestStreamUsage(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(data, &body))
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte(`data: {"choices":[{"index":0,"delta":{"content":"Hi"},"finish_reason":"stop"}]}` + "\n\n" +
+			`data: {"choices":[],"usage":{"prompt_tokens":12,"completion_tokens":5,"total_tokens":17,` +
+			`"prompt_cache_hit_tokens":8,"prompt_cache_miss_tokens":4}}` + "\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	metrics := deepseek.NewInMemoryMetrics()
+	recorder := &recordingTracer{}
+	client, err := deepseek.NewClient("",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithKeyPool(deepseek.KeyPool{Keys: []deepseek.APIKey{{Key: "sk-pool-key"}}}),
+		deepseek.WithMetrics(metrics),
+		deepseek.WithTracer(recorder.tracer()),
+		deepseek.WithRateLimit(deepseek.RateLimit{TokensPerMinute: 1000}),
+	)
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages:      []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+		MaxTokens:     600,
+		StreamOptions: &deepseek.StreamOptions{IncludeUsage: true},
+	}
+
+	// Both streams are estimated at more than half of the budget, so the second
+	// one only fits once the first has been corrected by its reported usage
+	for i := 0; i < 2; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		stream, err := client.CreateChatCompletionStream(ctx, req)
+		require.NoError(t, err)
+		assert.Nil(t, stream.Usage())
+
+		content, err := deepseek.CollectFullResponse(stream)
+		cancel()
+		require.NoError(t, err)
+		assert.Equal(t, "Hi", content)
+		assert.Equal(t, &deepseek.Usage{
+			PromptTokens:          12,
+			CompletionTokens:      5,
+			TotalTokens:           17,
+			PromptCacheHitTokens:  8,
+			PromptCacheMissTokens: 4,
+		}, stream.Usage())
+	}
+
+	assert.Equal(t, map[string]any{"include_usage": true}, body["stream_options"])
+	assert.Equal(t, int64(34), client.KeyStats()[0].TotalTokens)
+
+	snapshot := metrics.Snapshot()
+	require.Len(t, snapshot.Streams, 1)
+	assert.Equal(t, int64(24), snapshot.Streams[0].PromptTokens)
+	assert.Equal(t, int64(10), snapshot.Streams[0].CompletionTokens)
+
+	span := recorder.spans[0]
+	assert.Equal(t, 12, span.attrs[deepseek.AttrGenAIUsageInputTokens])
+	assert.Equal(t, 5, span.attrs[deepseek.AttrGenAIUsageOutputTokens])
+	assert.Equal(t, 8, span.attrs[deepseek.AttrUsageCacheHitTokens])
+	assert.Equal(t, 4, span.attrs[deepseek.AttrUsageCacheMissTokens])
+}
+
+func TestStreamOptionsOnlySentWhenStreaming(t *testing.T) {
+	var body map[string]any
+	server := toolServer(t, `{"choices":[],"usage":{"prompt_tokens":3,"prompt_cache_hit_tokens":2,"prompt_cache_miss_tokens":1}}`, &body)
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages:      []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+		StreamOptions: &deepseek.StreamOptions{IncludeUsage: true},
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, body, "stream_options")
+	assert.Equal(t, 2, resp.Usage.PromptCacheHitTokens)
+	assert.Equal(t, 1, resp.Usage.PromptCacheMissTokens)
+}
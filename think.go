@@ -0,0 +1,157 @@
+package deepseek
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	thinkOpenTag  = "<think>"
+	thinkCloseTag = "</think>"
+)
+
+// WithThinkTagParsing moves reasoning returned inline as <think>...</think> in
+// the content, as done by R1-distilled models on OpenAI-compatible servers,
+// into ReasoningContent of messages and stream deltas. Content that only has
+// the closing tag, because the opening one was part of the prompt template, is
+// handled too; streams therefore hold content back until the first tag.
+func WithThinkTagParsing() ClientOption {
+	return func(c *Client) {
+		c.parseThinkTags = true
+	}
+}
+
+// splitThinkTags moves the <think> blocks of the messages of resp into their reasoning content
+func splitThinkTags(resp *ChatCompletionResponse) {
+	for i := range resp.Choices {
+		msg := &resp.Choices[i].Message
+		if msg.ReasoningContent != "" || !strings.Contains(msg.Content, thinkCloseTag) {
+			continue
+		}
+
+		var p thinkParser
+		answer, reasoning := p.feed(msg.Content)
+		tailAnswer, tailReasoning := p.flush()
+		msg.Content = answer + tailAnswer
+		msg.ReasoningContent = strings.TrimRightFunc(reasoning+tailReasoning, unicode.IsSpace)
+	}
+}
+
+// thinkParser splits streamed content into answer and reasoning. Text that
+// may be the start of a tag split across chunks is held back until the next
+// chunk shows whether it is one.
+type thinkParser struct {
+	// started is set once the first tag has been seen. Until then content is
+	// held back, as a closing tag without an opening one makes it reasoning.
+	started  bool
+	inThink  bool
+	pending  string
+	trimNext bool
+}
+
+// feed parses the next piece of content
+func (p *thinkParser) feed(text string) (answer, reasoning string) {
+	buf := p.pending + text
+	p.pending = ""
+
+	if !p.started {
+		open := strings.Index(buf, thinkOpenTag)
+		end := strings.Index(buf, thinkCloseTag)
+		switch {
+		case end >= 0 && (open < 0 || end < open):
+			// The opening tag was part of the prompt
+			p.started = true
+			p.inThink = true
+			p.trimNext = true
+		case open >= 0:
+			p.started = true
+		default:
+			p.pending = buf
+			return "", ""
+		}
+	}
+
+	var a, r strings.Builder
+	emit := func(s string) {
+		if p.trimNext {
+			s = strings.TrimLeftFunc(s, unicode.IsSpace)
+			if s == "" {
+				return
+			}
+			p.trimNext = false
+		}
+		if p.inThink {
+			r.WriteString(s)
+		} else {
+			a.WriteString(s)
+		}
+	}
+
+	for buf != "" {
+		tag := thinkOpenTag
+		if p.inThink {
+			tag = thinkCloseTag
+		}
+		if i := strings.Index(buf, tag); i >= 0 {
+			emit(buf[:i])
+			buf = buf[i+len(tag):]
+			p.inThink = !p.inThink
+			p.trimNext = true
+			continue
+		}
+		n := partialTagSuffix(buf, tag)
+		emit(buf[:len(buf)-n])
+		p.pending = buf[len(buf)-n:]
+		break
+	}
+	return a.String(), r.String()
+}
+
+// flush returns the text held back at the end of the content
+func (p *thinkParser) flush() (answer, reasoning string) {
+	pending := p.pending
+	p.pending = ""
+	if p.trimNext {
+		pending = strings.TrimLeftFunc(pending, unicode.IsSpace)
+	}
+	if p.inThink {
+		return "", pending
+	}
+	return pending, ""
+}
+
+// partialTagSuffix returns the length of the longest suffix of s that is a
+// proper prefix of tag
+func partialTagSuffix(s, tag string) int {
+	for n := len(tag) - 1; n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// splitThinkTags moves the <think> blocks of the deltas of chunk into
+// their reasoning content, keeping a parser per choice
+func (s *Stream) splitThinkTags(chunk *StreamResponse) {
+	if s.think == nil {
+		s.think = make(map[int]*thinkParser)
+	}
+	for i := range chunk.Choices {
+		choice := &chunk.Choices[i]
+		p := s.think[choice.Index]
+		if p == nil {
+			p = &thinkParser{}
+			s.think[choice.Index] = p
+		}
+
+		answer, reasoning := p.feed(choice.Delta.Content)
+		if choice.FinishReason != "" {
+			tailAnswer, tailReasoning := p.flush()
+			answer += tailAnswer
+			reasoning += tailReasoning
+		}
+		choice.Delta.Content = answer
+		choice.Delta.ReasoningContent += reasoning
+	}
+}
@@ -0,0 +1,106 @@
+package deepseek_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func TestThinkTagParsing(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		answer    string
+		reasoning string
+	}{
+		{"tags", "<think>\nCompare decimals.\n</think>\n\n9.8 is greater", "9.8 is greater", "Compare decimals."},
+		{"only closing tag", "Compare decimals.</think>9.8 is greater", "9.8 is greater", "Compare decimals."},
+		{"no tags", "9.8 is greater", "9.8 is greater", ""},
+		{"unterminated", "<think>Compare", "<think>Compare", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := json.Marshal(tt.content)
+			require.NoError(t, err)
+			var body map[string]any
+			server := toolServer(t, fmt.Sprintf(`{"choices":[{"message":{"role":"assistant","content":%s}}]}`, content), &body)
+
+			client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL), deepseek.WithThinkTagParsing())
+			require.NoError(t, err)
+			resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+				Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "9.11 or 9.8?"}},
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.answer, resp.Choices[0].Message.Content)
+			assert.Equal(t, tt.reasoning, resp.Choices[0].Message.ReasoningContent)
+		})
+	}
+}
+
+func TestThinkTagParsingDisabled(t *testing.T) {
+	var body map[string]any
+	server := toolServer(t, `{"choices":[{"message":{"content":"<think>hmm</think>answer"}}]}`, &body)
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "<think>hmm</think>answer", resp.Choices[0].Message.Content)
+}
+
+func TestStreamThinkTagParsing(t *testing.T) {
+	// Tags split across chunks, and a "<" in the answer that is not a tag
+	pieces := []string{"<th", "ink>\nCompare ", "decimals.</thi", "nk>\n\n", "9.8 <", " 9.11 is ", "false <"}
+	var chunks []string
+	for i, piece := range pieces {
+		content, err := json.Marshal(piece)
+		require.NoError(t, err)
+		finish := ""
+		if i == len(pieces)-1 {
+			finish = `,"finish_reason":"stop"`
+		}
+		chunks = append(chunks, fmt.Sprintf(`{"choices":[{"index":0,"delta":{"content":%s}%s}]}`, content, finish))
+	}
+	server := sseServer(t, chunks...)
+
+	msg, err := deepseek.CollectMessage(openStream(t, server, deepseek.WithThinkTagParsing()))
+	require.NoError(t, err)
+	assert.Equal(t, "Compare decimals.", msg.ReasoningContent)
+	assert.Equal(t, "9.8 < 9.11 is false <", msg.Content)
+
+	msg, err = deepseek.CollectMessage(openStream(t, server))
+	require.NoError(t, err)
+	assert.Equal(t, "<think>\nCompare decimals.</think>\n\n9.8 < 9.11 is false <", msg.Content)
+	assert.Empty(t, msg.ReasoningContent)
+}
+
+func TestStreamThinkTagParsingOnlyClosingTag(t *testing.T) {
+	// The prompt template opened the block, so only the closing tag is streamed
+	server := sseServer(t,
+		`{"choices":[{"index":0,"delta":{"content":"reasoning here"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"</think>"}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"answer"},"finish_reason":"stop"}]}`,
+	)
+
+	msg, err := deepseek.CollectMessage(openStream(t, server, deepseek.WithThinkTagParsing()))
+	require.NoError(t, err)
+	assert.Equal(t, "reasoning here", msg.ReasoningContent)
+	assert.Equal(t, "answer", msg.Content)
+
+	// Without tags, the held back content is the answer
+	server = sseServer(t,
+		`{"choices":[{"index":0,"delta":{"content":"9.8 is "}}]}`,
+		`{"choices":[{"index":0,"delta":{"content":"greater"},"finish_reason":"stop"}]}`,
+	)
+	msg, err = deepseek.CollectMessage(openStream(t, server, deepseek.WithThinkTagParsing()))
+	require.NoError(t, err)
+	assert.Equal(t, "9.8 is greater", msg.Content)
+	assert.Empty(t, msg.ReasoningContent)
+}
@@ -50,6 +50,12 @@ func (c *Client) EstimateTokensFromMessages(messages []Message) *TokenEstimate {
 			totalTokens += 3 // Approximate tokens for function name
 			totalTokens += c.EstimateTokenCount(string(msg.FunctionCall.Arguments)).EstimatedTokens
 		}
+
+		// Add tokens for tool calls
+		for _, call := range msg.ToolCalls {
+			totalTokens += 3 // Approximate tokens for call ID and function name
+			totalTokens += c.EstimateTokenCount(call.Function.Arguments).EstimatedTokens
+		}
 	}
 
 	return &TokenEstimate{
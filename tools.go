@@ -0,0 +1,222 @@
+package deepseek
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/trustsight-io/deepseek-go/internal/errors"
+)
+
+// ToolTypeFunction is the type of function tools and tool calls
+const ToolTypeFunction = "function"
+
+// ToolCall is a call of a tool requested by the model. An assistant message
+// can request several calls at once.
+type ToolCall struct {
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction is the function a tool call invokes
+type ToolCallFunction struct {
+	Name string `json:"name"`
+	// Arguments holds the arguments as JSON text, as generated by the model
+	Arguments string `json:"arguments"`
+}
+
+// NewToolMessage returns the message answering the tool call with the given ID
+func NewToolMessage(toolCallID, content string) Message {
+	return Message{Role: RoleTool, Content: content, ToolCallID: toolCallID}
+}
+
+// ToolChoiceMode controls whether the model calls tools
+type ToolChoiceMode string
+
+const (
+	// ToolChoiceModeAuto lets the model decide whether to call tools
+	ToolChoiceModeAuto ToolChoiceMode = "auto"
+	// ToolChoiceModeNone prevents the model from calling tools
+	ToolChoiceModeNone ToolChoiceMode = "none"
+	// ToolChoiceModeRequired makes the model call at least one tool
+	ToolChoiceModeRequired ToolChoiceMode = "required"
+)
+
+// ToolChoice controls which tool the model calls. It is either a mode or the
+// name of a function the model must call.
+type ToolChoice struct {
+	Mode     ToolChoiceMode
+	Function string
+}
+
+// AutoToolChoice lets the model decide whether to call tools
+func AutoToolChoice() *ToolChoice {
+	return &ToolChoice{Mode: ToolChoiceModeAuto}
+}
+
+// NoToolChoice prevents the model from calling tools
+func NoToolChoice() *ToolChoice {
+	return &ToolChoice{Mode: ToolChoiceModeNone}
+}
+
+// RequiredToolChoice makes the model call at least one tool
+func RequiredToolChoice() *ToolChoice {
+	return &ToolChoice{Mode: ToolChoiceModeRequired}
+}
+
+// FunctionToolChoice makes the model call the named function
+func FunctionToolChoice(name string) *ToolChoice {
+	return &ToolChoice{Function: name}
+}
+
+type namedToolChoice struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// MarshalJSON encodes a mode as a string and a function as a named tool choice
+func (tc ToolChoice) MarshalJSON() ([]byte, error) {
+	if tc.Function == "" {
+		return json.Marshal(string(tc.Mode))
+	}
+	named := namedToolChoice{Type: ToolTypeFunction}
+	named.Function.Name = tc.Function
+	return json.Marshal(named)
+}
+
+// UnmarshalJSON decodes a mode string or a named tool choice
+func (tc *ToolChoice) UnmarshalJSON(data []byte) error {
+	var mode string
+	if err := json.Unmarshal(data, &mode); err == nil {
+		*tc = ToolChoice{Mode: ToolChoiceMode(mode)}
+		return nil
+	}
+	var named namedToolChoice
+	if err := json.Unmarshal(data, &named); err != nil {
+		return fmt.Errorf("invalid tool_choice: %w", err)
+	}
+	*tc = ToolChoice{Function: named.Function.Name}
+	return nil
+}
+
+// validateToolMessages checks that tool messages name the call they answer
+func validateToolMessages(messages []Message) error {
+	for i, msg := range messages {
+		if msg.Role == RoleTool && msg.ToolCallID == "" {
+			return &errors.InvalidRequestError{
+				Param: fmt.Sprintf("messages[%d].tool_call_id", i),
+				Err:   fmt.Errorf("is required for tool messages"),
+			}
+		}
+	}
+	return nil
+}
+
+// usesLegacyFunctions reports whether the request uses the deprecated
+// functions protocol
+func (r *ChatCompletionRequest) usesLegacyFunctions() bool {
+	if len(r.Functions) > 0 || r.FunctionCall != "" {
+		return true
+	}
+	for _, msg := range r.Messages {
+		if msg.Role == RoleFunction || msg.FunctionCall != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// withTools returns the request translated from the deprecated functions
+// protocol to tools, or r itself when it does not use functions. The caller's
+// request and messages are not modified.
+func (r *ChatCompletionRequest) withTools() *ChatCompletionRequest {
+	if !r.usesLegacyFunctions() {
+		return r
+	}
+
+	tr := *r
+	tr.Tools = append([]Tool(nil), r.Tools...)
+	for i := range r.Functions {
+		tr.Tools = append(tr.Tools, Tool{Type: ToolTypeFunction, Function: &r.Functions[i]})
+	}
+	tr.Functions = nil
+
+	if r.FunctionCall != "" && tr.ToolChoice == nil {
+		tr.ToolChoice = legacyToolChoice(r.FunctionCall)
+	}
+	tr.FunctionCall = ""
+
+	// Function calls have no IDs, so each is given one that the function
+	// message answering it refers to
+	tr.Messages = make([]Message, len(r.Messages))
+	callIDs := make(map[string]string)
+	for i, msg := range r.Messages {
+		switch {
+		case msg.FunctionCall != nil:
+			id := fmt.Sprintf("call_%d", i)
+			callIDs[msg.FunctionCall.Name] = id
+			msg.ToolCalls = append(msg.ToolCalls, ToolCall{
+				ID:   id,
+				Type: ToolTypeFunction,
+				Function: ToolCallFunction{
+					Name:      msg.FunctionCall.Name,
+					Arguments: legacyArguments(msg.FunctionCall.Arguments),
+				},
+			})
+			msg.FunctionCall = nil
+		case msg.Role == RoleFunction:
+			msg.Role = RoleTool
+			if msg.ToolCallID == "" {
+				msg.ToolCallID = callIDs[msg.Name]
+			}
+			msg.Name = ""
+		}
+		tr.Messages[i] = msg
+	}
+	return &tr
+}
+
+// legacyToolChoice converts the function_call field of the functions protocol
+func legacyToolChoice(functionCall string) *ToolChoice {
+	switch functionCall {
+	case "auto", "none":
+		return &ToolChoice{Mode: ToolChoiceMode(functionCall)}
+	}
+	var named struct {
+		Name string `json:"name"`
+	}
+	if strings.HasPrefix(strings.TrimSpace(functionCall), "{") &&
+		json.Unmarshal([]byte(functionCall), &named) == nil {
+		return FunctionToolChoice(named.Name)
+	}
+	return FunctionToolChoice(functionCall)
+}
+
+// legacyArguments returns the arguments of a function call as JSON text. They
+// may be given as a JSON value or, as sent by the API, a JSON string.
+func legacyArguments(args json.RawMessage) string {
+	var text string
+	if json.Unmarshal(args, &text) == nil {
+		return text
+	}
+	return string(args)
+}
+
+// setLegacyFunctionCalls fills FunctionCall on messages with tool calls, for
+// callers of the functions protocol
+func setLegacyFunctionCalls(resp *ChatCompletionResponse) {
+	for i := range resp.Choices {
+		msg := &resp.Choices[i].Message
+		if msg.FunctionCall != nil || len(msg.ToolCalls) == 0 {
+			continue
+		}
+		args := json.RawMessage(msg.ToolCalls[0].Function.Arguments)
+		if !json.Valid(args) {
+			args, _ = json.Marshal(msg.ToolCalls[0].Function.Arguments)
+		}
+		msg.FunctionCall = &FunctionCall{Name: msg.ToolCalls[0].Function.Name, Arguments: args}
+	}
+}
@@ -0,0 +1,173 @@
+package deepseek_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+const toolCallResponse = `{
+	"id": "chat-1",
+	"choices": [{
+		"index": 0,
+		"message": {
+			"role": "assistant",
+			"content": null,
+			"tool_calls": [
+				{"id": "call_a", "type": "function", "function": {"name": "get_weather", "arguments": "{\"location\":\"Paris\"}"}},
+				{"id": "call_b", "type": "function", "function": {"name": "get_weather", "arguments": "{\"location\":\"Tokyo\"}"}}
+			]
+		},
+		"finish_reason": "tool_calls"
+	}]
+}`
+
+// toolServer records the body of each request and answers with response
+func toolServer(t *testing.T, response string, body *map[string]any) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		*body = nil
+		require.NoError(t, json.Unmarshal(data, body))
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+var weatherFunction = deepseek.Function{
+	Name:        "get_weather",
+	Description: "Get the weather of a location",
+	Parameters:  json.RawMessage(`{"type":"object","properties":{"location":{"type":"string"}}}`),
+}
+
+func TestToolChoiceJSON(t *testing.T) {
+	tests := []struct {
+		choice *deepseek.ToolChoice
+		json   string
+	}{
+		{deepseek.AutoToolChoice(), `"auto"`},
+		{deepseek.NoToolChoice(), `"none"`},
+		{deepseek.RequiredToolChoice(), `"required"`},
+		{deepseek.FunctionToolChoice("get_weather"), `{"type":"function","function":{"name":"get_weather"}}`},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.choice)
+		require.NoError(t, err)
+		assert.JSONEq(t, tt.json, string(data))
+
+		var decoded deepseek.ToolChoice
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, *tt.choice, decoded)
+	}
+}
+
+func TestToolCalls(t *testing.T) {
+	var body map[string]any
+	server := toolServer(t, toolCallResponse, &body)
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	messages := []deepseek.Message{{Role: deepseek.RoleUser, Content: "Weather in Paris and Tokyo?"}}
+	resp, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages:   messages,
+		Tools:      []deepseek.Tool{{Type: deepseek.ToolTypeFunction, Function: &weatherFunction}},
+		ToolChoice: deepseek.RequiredToolChoice(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "required", body["tool_choice"])
+
+	msg := resp.Choices[0].Message
+	require.Len(t, msg.ToolCalls, 2)
+	assert.Equal(t, "call_b", msg.ToolCalls[1].ID)
+	assert.Equal(t, "get_weather", msg.ToolCalls[1].Function.Name)
+	assert.JSONEq(t, `{"location":"Tokyo"}`, msg.ToolCalls[1].Function.Arguments)
+	assert.Nil(t, msg.FunctionCall)
+
+	messages = append(messages, msg,
+		deepseek.NewToolMessage("call_a", "sunny"),
+		deepseek.NewToolMessage("call_b", "rainy"),
+	)
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: messages,
+		Tools:    []deepseek.Tool{{Type: deepseek.ToolTypeFunction, Function: &weatherFunction}},
+	})
+	require.NoError(t, err)
+
+	sent := body["messages"].([]any)
+	require.Len(t, sent, 4)
+	assistant := sent[1].(map[string]any)
+	assert.Len(t, assistant["tool_calls"], 2)
+	tool := sent[3].(map[string]any)
+	assert.Equal(t, "tool", tool["role"])
+	assert.Equal(t, "call_b", tool["tool_call_id"])
+	assert.Equal(t, "rainy", tool["content"])
+	assert.NotContains(t, body, "tool_choice")
+}
+
+func TestToolMessageRequiresID(t *testing.T) {
+	client, err := deepseek.NewClient("test-key")
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleTool, Content: "sunny"}},
+	})
+	var reqErr *deepseek.InvalidRequestError
+	require.ErrorAs(t, err, &reqErr)
+	assert.Equal(t, "messages[0].tool_call_id", reqErr.Param)
+}
+
+func TestLegacyFunctionsTranslated(t *testing.T) {
+	var body map[string]any
+	server := toolServer(t, toolCallResponse, &body)
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	req := &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{
+			{Role: deepseek.RoleUser, Content: "Weather in Paris?"},
+			{Role: deepseek.RoleAssistant, FunctionCall: &deepseek.FunctionCall{
+				Name:      "get_weather",
+				Arguments: json.RawMessage(`{"location":"Paris"}`),
+			}},
+			{Role: deepseek.RoleFunction, Name: "get_weather", Content: "sunny"},
+		},
+		Functions:    []deepseek.Function{weatherFunction},
+		FunctionCall: "get_weather",
+	}
+	resp, err := client.CreateChatCompletion(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.NotContains(t, body, "functions")
+	assert.NotContains(t, body, "function_call")
+	tools := body["tools"].([]any)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "get_weather", tools[0].(map[string]any)["function"].(map[string]any)["name"])
+	assert.Equal(t, map[string]any{"type": "function", "function": map[string]any{"name": "get_weather"}}, body["tool_choice"])
+
+	sent := body["messages"].([]any)
+	call := sent[1].(map[string]any)["tool_calls"].([]any)[0].(map[string]any)
+	assert.JSONEq(t, `{"location":"Paris"}`, call["function"].(map[string]any)["arguments"].(string))
+	tool := sent[2].(map[string]any)
+	assert.Equal(t, "tool", tool["role"])
+	assert.Equal(t, call["id"], tool["tool_call_id"])
+	assert.NotContains(t, tool, "name")
+
+	// The response is readable through the functions protocol too
+	fc := resp.Choices[0].Message.FunctionCall
+	require.NotNil(t, fc)
+	assert.Equal(t, "get_weather", fc.Name)
+	assert.JSONEq(t, `{"location":"Paris"}`, string(fc.Arguments))
+
+	// The caller's request is left as it was
+	assert.Len(t, req.Functions, 1)
+	assert.Equal(t, deepseek.RoleFunction, req.Messages[2].Role)
+	assert.Empty(t, req.Messages[1].ToolCalls)
+}
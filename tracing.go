@@ -0,0 +1,342 @@
+package deepseek
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/trustsight-io/deepseek-go/internal/errors"
+)
+
+// Attribute keys following the OpenTelemetry GenAI semantic conventions
+const (
+	AttrGenAISystem             = "gen_ai.system"
+	AttrGenAIOperationName      = "gen_ai.operation.name"
+	AttrGenAIRequestModel       = "gen_ai.request.model"
+	AttrGenAIRequestTemperature = "gen_ai.request.temperature"
+	AttrGenAIRequestTopP        = "gen_ai.request.top_p"
+	AttrGenAIRequestMaxTokens   = "gen_ai.request.max_tokens"
+	AttrGenAIRequestPresence    = "gen_ai.request.presence_penalty"
+	AttrGenAIRequestFrequency   = "gen_ai.request.frequency_penalty"
+	AttrGenAIRequestStop        = "gen_ai.request.stop_sequences"
+	AttrGenAIRequestSeed        = "gen_ai.request.seed"
+	AttrGenAIResponseID         = "gen_ai.response.id"
+	AttrGenAIResponseModel      = "gen_ai.response.model"
+	AttrGenAIResponseFinish     = "gen_ai.response.finish_reasons"
+	AttrGenAIUsageInputTokens   = "gen_ai.usage.input_tokens"
+	AttrGenAIUsageOutputTokens  = "gen_ai.usage.output_tokens"
+	AttrUsageCacheHitTokens     = "deepseek.usage.prompt_cache_hit_tokens"
+	AttrUsageCacheMissTokens    = "deepseek.usage.prompt_cache_miss_tokens"
+	AttrServerAddress           = "server.address"
+	AttrServerPort              = "server.port"
+	AttrHTTPStatusCode          = "http.response.status_code"
+	AttrErrorType               = "error.type"
+	AttrRetryAttempts           = "deepseek.request.attempts"
+	AttrStreamTimeToFirstToken  = "deepseek.stream.time_to_first_token"
+)
+
+// Span events recorded by the client
+const (
+	EventRetry      = "deepseek.retry"
+	EventFirstToken = "deepseek.stream.first_token"
+	EventHedge      = "deepseek.hedge"
+)
+
+// Attribute is a key-value pair attached to a span
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts spans for client calls and propagates trace context.
+// It has no third-party dependencies; use TracerAdapter to plug in a tracing
+// library such as OpenTelemetry.
+type Tracer interface {
+	// Start starts a span and returns a context carrying it
+	Start(ctx context.Context, name string) (context.Context, Span)
+	// Inject writes the trace context carried by ctx into outgoing HTTP headers
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a unit of work within a trace
+type Span interface {
+	// SetAttributes sets attributes on the span
+	SetAttributes(attrs ...Attribute)
+	// AddEvent records a timestamped event on the span
+	AddEvent(name string, attrs ...Attribute)
+	// RecordError marks the span as failed with err
+	RecordError(err error)
+	// End completes the span
+	End()
+}
+
+// TracerAdapter implements Tracer with functions, bridging tracing libraries such
+// as OpenTelemetry. Nil functions are no-ops.
+type TracerAdapter struct {
+	StartFunc  func(ctx context.Context, name string) (context.Context, Span)
+	InjectFunc func(ctx context.Context, header http.Header)
+}
+
+// Start calls StartFunc
+func (t TracerAdapter) Start(ctx context.Context, name string) (context.Context, Span) {
+	if t.StartFunc == nil {
+		return ctx, noopSpan{}
+	}
+	ctx, span := t.StartFunc(ctx, name)
+	if span == nil {
+		span = noopSpan{}
+	}
+	return ctx, span
+}
+
+// Inject calls InjectFunc
+func (t TracerAdapter) Inject(ctx context.Context, header http.Header) {
+	if t.InjectFunc != nil {
+		t.InjectFunc(ctx, header)
+	}
+}
+
+// SpanAdapter implements Span with functions. Nil functions are no-ops.
+type SpanAdapter struct {
+	SetAttributesFunc func(attrs ...Attribute)
+	AddEventFunc      func(name string, attrs ...Attribute)
+	RecordErrorFunc   func(err error)
+	EndFunc           func()
+}
+
+// SetAttributes calls SetAttributesFunc
+func (s SpanAdapter) SetAttributes(attrs ...Attribute) {
+	if s.SetAttributesFunc != nil {
+		s.SetAttributesFunc(attrs...)
+	}
+}
+
+// AddEvent calls AddEventFunc
+func (s SpanAdapter) AddEvent(name string, attrs ...Attribute) {
+	if s.AddEventFunc != nil {
+		s.AddEventFunc(name, attrs...)
+	}
+}
+
+// RecordError calls RecordErrorFunc
+func (s SpanAdapter) RecordError(err error) {
+	if s.RecordErrorFunc != nil {
+		s.RecordErrorFunc(err)
+	}
+}
+
+// End calls EndFunc
+func (s SpanAdapter) End() {
+	if s.EndFunc != nil {
+		s.EndFunc()
+	}
+}
+
+// WithTracer enables tracing. Every call creates a span and its trace context is
+// propagated through HTTP headers.
+func WithTracer(tracer Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = tracer
+	}
+}
+
+// noopSpan is used when tracing is disabled
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(...Attribute)    {}
+func (noopSpan) AddEvent(string, ...Attribute) {}
+func (noopSpan) RecordError(error)             {}
+func (noopSpan) End()                          {}
+
+// startSpan starts the span of a call and sets its request attributes
+func (c *Client) startSpan(ctx context.Context, req *Request) (context.Context, Span) {
+	if c.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	operation := operationName(req.Path)
+	attrs := []Attribute{
+		{AttrGenAISystem, "deepseek"},
+		{AttrGenAIOperationName, operation},
+	}
+	name := operation
+
+	switch body := req.Body.(type) {
+	case *ChatCompletionRequest:
+		name += " " + body.Model
+		attrs = append(attrs, Attribute{AttrGenAIRequestModel, body.Model})
+		attrs = appendNonZero(attrs, AttrGenAIRequestTemperature, body.Temperature)
+		attrs = appendNonZero(attrs, AttrGenAIRequestTopP, body.TopP)
+		attrs = appendNonZero(attrs, AttrGenAIRequestPresence, body.PresencePenalty)
+		attrs = appendNonZero(attrs, AttrGenAIRequestFrequency, body.FrequencyPenalty)
+		if body.MaxTokens > 0 {
+			attrs = append(attrs, Attribute{AttrGenAIRequestMaxTokens, body.MaxTokens})
+		}
+		if len(body.Stop) > 0 {
+			attrs = append(attrs, Attribute{AttrGenAIRequestStop, body.Stop})
+		}
+		if body.Seed != 0 {
+			attrs = append(attrs, Attribute{AttrGenAIRequestSeed, body.Seed})
+		}
+	case *CompletionRequest:
+		name += " " + body.Model
+		attrs = append(attrs, Attribute{AttrGenAIRequestModel, body.Model})
+		attrs = appendNonZero(attrs, AttrGenAIRequestTemperature, float64(body.Temperature))
+		attrs = appendNonZero(attrs, AttrGenAIRequestTopP, float64(body.TopP))
+		if body.MaxTokens > 0 {
+			attrs = append(attrs, Attribute{AttrGenAIRequestMaxTokens, body.MaxTokens})
+		}
+	}
+
+	ctx, span := c.tracer.Start(ctx, name)
+	span.SetAttributes(attrs...)
+	return ctx, span
+}
+
+// endSpan sets the response attributes of a call and ends its span. The server
+// attributes name the base URL of the last attempt, which may be a failover endpoint.
+func endSpan(span Span, state *callState, resp *Response, err error) {
+	attrs := []Attribute{{AttrRetryAttempts, state.attempts}}
+	if u, perr := url.Parse(state.baseURL); perr == nil && state.baseURL != "" {
+		attrs = append(attrs, serverAttributes(u)...)
+	}
+	if resp != nil {
+		attrs = append(attrs, Attribute{AttrHTTPStatusCode, resp.StatusCode})
+		switch body := resp.Body.(type) {
+		case *ChatCompletionResponse:
+			reasons := make([]string, 0, len(body.Choices))
+			for _, choice := range body.Choices {
+				reasons = append(reasons, choice.FinishReason)
+			}
+			attrs = append(attrs,
+				Attribute{AttrGenAIResponseID, body.ID},
+				Attribute{AttrGenAIResponseModel, body.Model},
+				Attribute{AttrGenAIResponseFinish, reasons},
+			)
+			attrs = append(attrs, usageAttributes(body.Usage)...)
+		case *CompletionResponse:
+			reasons := make([]string, 0, len(body.Choices))
+			for _, choice := range body.Choices {
+				reasons = append(reasons, choice.FinishReason)
+			}
+			attrs = append(attrs,
+				Attribute{AttrGenAIResponseID, body.ID},
+				Attribute{AttrGenAIResponseModel, body.Model},
+				Attribute{AttrGenAIResponseFinish, reasons},
+			)
+			attrs = append(attrs, usageAttributes(body.Usage)...)
+		}
+	}
+	span.SetAttributes(attrs...)
+
+	if err != nil {
+		span.SetAttributes(Attribute{AttrErrorType, errorType(err)})
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// usageAttributes returns the token usage attributes of a response
+func usageAttributes(usage Usage) []Attribute {
+	attrs := []Attribute{
+		{AttrGenAIUsageInputTokens, usage.PromptTokens},
+		{AttrGenAIUsageOutputTokens, usage.CompletionTokens},
+	}
+	if usage.PromptCacheHitTokens > 0 || usage.PromptCacheMissTokens > 0 {
+		attrs = append(attrs,
+			Attribute{AttrUsageCacheHitTokens, usage.PromptCacheHitTokens},
+			Attribute{AttrUsageCacheMissTokens, usage.PromptCacheMissTokens},
+		)
+	}
+	return attrs
+}
+
+// serverAttributes returns the server address and port of a base URL
+func serverAttributes(u *url.URL) []Attribute {
+	attrs := []Attribute{{AttrServerAddress, u.Hostname()}}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, Attribute{AttrServerPort, p})
+	}
+	return attrs
+}
+
+// operationName maps an API path to its GenAI operation name
+func operationName(path string) string {
+	switch path {
+	case "/chat/completions":
+		return "chat"
+	case "/completions":
+		return "text_completion"
+	default:
+		return path
+	}
+}
+
+// errorType returns a low-cardinality description of err for the error.type attribute
+func errorType(err error) string {
+	switch e := err.(type) {
+	case *CircuitOpenError:
+		return "circuit_open"
+	case *RequestTooLargeError:
+		return "request_too_large"
+	case *attemptError:
+		if ne, ok := e.err.(net.Error); ok && ne.Timeout() {
+			return "timeout"
+		}
+		return "connection"
+	case *errors.AuthenticationError:
+		return errors.ErrorTypeAuthentication
+	case *errors.InsufficientBalanceError:
+		return errors.ErrorTypeInsufficientBalance
+	case *errors.RateLimitError:
+		return errors.ErrorTypeRateLimit
+	case *errors.InvalidRequestError:
+		return errors.ErrorTypeInvalidRequest
+	case *errors.InvalidFormatError:
+		return errors.ErrorTypeInvalidFormat
+	case *errors.InvalidParametersError:
+		return errors.ErrorTypeInvalidParameters
+	case *errors.ContextLengthExceededError:
+		return errors.ErrorTypeContextLengthExceeded
+	case *errors.ServerError:
+		return errors.ErrorTypeServer
+	case *errors.ServerOverloadedError:
+		return errors.ErrorTypeServerOverloaded
+	case *errors.ModelNotFoundError:
+		return errors.ErrorTypeModelNotFound
+	case *errors.RequestError:
+		return strconv.Itoa(e.StatusCode)
+	case *errors.APIError:
+		return strconv.Itoa(e.StatusCode)
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return err.Error()
+	}
+	return "_OTHER"
+}
+
+func appendNonZero(attrs []Attribute, key string, value float64) []Attribute {
+	if value == 0 {
+		return attrs
+	}
+	return append(attrs, Attribute{key, value})
+}
+
+// addRetryEvent records a retry on the span of a call
+func addRetryEvent(span Span, state *callState, delay time.Duration, err error) {
+	span.AddEvent(EventRetry,
+		Attribute{"attempt", state.attempts},
+		Attribute{"delay", delay.String()},
+		Attribute{AttrErrorType, errorType(err)},
+	)
+}
@@ -0,0 +1,203 @@
+package deepseek_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+// recordedSpan collects what the client reports on a span
+type recordedSpan struct {
+	name   string
+	attrs  map[string]interface{}
+	events []string
+	err    error
+	ended  bool
+}
+
+// recordingTracer builds a Tracer from TracerAdapter and SpanAdapter, the way an
+// OpenTelemetry bridge would
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+type spanKey struct{}
+
+func (r *recordingTracer) tracer() deepseek.Tracer {
+	return deepseek.TracerAdapter{
+		StartFunc: func(ctx context.Context, name string) (context.Context, deepseek.Span) {
+			span := &recordedSpan{name: name, attrs: map[string]interface{}{}}
+			r.mu.Lock()
+			r.spans = append(r.spans, span)
+			r.mu.Unlock()
+			return context.WithValue(ctx, spanKey{}, span), deepseek.SpanAdapter{
+				SetAttributesFunc: func(attrs ...deepseek.Attribute) {
+					for _, a := range attrs {
+						span.attrs[a.Key] = a.Value
+					}
+				},
+				AddEventFunc: func(name string, attrs ...deepseek.Attribute) {
+					span.events = append(span.events, name)
+				},
+				RecordErrorFunc: func(err error) { span.err = err },
+				EndFunc:         func() { span.ended = true },
+			}
+		},
+		InjectFunc: func(ctx context.Context, header http.Header) {
+			if span, ok := ctx.Value(spanKey{}).(*recordedSpan); ok {
+				header.Set("traceparent", "00-trace-"+span.name)
+			}
+		},
+	}
+}
+
+func TestTracingChatCompletion(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "00-trace-chat deepseek-chat", r.Header.Get("traceparent"))
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"boom"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"chat-1","model":"deepseek-chat","choices":[{"message":{"content":"hi"},"finish_reason":"stop"}],` +
+			`"usage":{"prompt_tokens":3,"completion_tokens":4,"total_tokens":7}}`))
+	}))
+	defer server.Close()
+
+	recorder := &recordingTracer{}
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithRetryWaitTime(time.Millisecond),
+		deepseek.WithTracer(recorder.tracer()),
+	)
+	require.NoError(t, err)
+
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages:    []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+		Temperature: 0.5,
+		MaxTokens:   100,
+	})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.spans, 1)
+	span := recorder.spans[0]
+	assert.Equal(t, "chat deepseek-chat", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, "deepseek", span.attrs[deepseek.AttrGenAISystem])
+	assert.Equal(t, "chat", span.attrs[deepseek.AttrGenAIOperationName])
+	assert.Equal(t, "deepseek-chat", span.attrs[deepseek.AttrGenAIRequestModel])
+	assert.Equal(t, 0.5, span.attrs[deepseek.AttrGenAIRequestTemperature])
+	assert.Equal(t, 100, span.attrs[deepseek.AttrGenAIRequestMaxTokens])
+	assert.Equal(t, "chat-1", span.attrs[deepseek.AttrGenAIResponseID])
+	assert.Equal(t, []string{"stop"}, span.attrs[deepseek.AttrGenAIResponseFinish])
+	assert.Equal(t, 3, span.attrs[deepseek.AttrGenAIUsageInputTokens])
+	assert.Equal(t, 4, span.attrs[deepseek.AttrGenAIUsageOutputTokens])
+	assert.Equal(t, 2, span.attrs[deepseek.AttrRetryAttempts])
+	assert.Equal(t, "127.0.0.1", span.attrs[deepseek.AttrServerAddress])
+	assert.Equal(t, []string{deepseek.EventRetry}, span.events)
+	assert.NoError(t, span.err)
+}
+
+func TestTracingStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("data: {\"id\":\"chat-2\",\"model\":\"deepseek-chat\",\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+			"data: {\"id\":\"chat-2\",\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}]}\n\ndata: [DONE]\n\n"))
+	}))
+	defer server.Close()
+
+	recorder := &recordingTracer{}
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithTracer(recorder.tracer()),
+	)
+	require.NoError(t, err)
+
+	stream, err := client.CreateChatCompletionStream(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "Hello!"}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, recorder.spans, 1)
+	span := recorder.spans[0]
+	assert.False(t, span.ended, "the span stays open while the stream is read")
+
+	_, err = deepseek.CollectFullResponse(stream)
+	require.NoError(t, err)
+
+	assert.True(t, span.ended)
+	assert.Contains(t, span.events, deepseek.EventFirstToken)
+	assert.Contains(t, span.attrs, deepseek.AttrStreamTimeToFirstToken)
+	assert.Equal(t, "chat-2", span.attrs[deepseek.AttrGenAIResponseID])
+	assert.Equal(t, []string{"stop"}, span.attrs[deepseek.AttrGenAIResponseFinish])
+}
+
+func TestTracingError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"invalid api key"}`))
+	}))
+	defer server.Close()
+
+	recorder := &recordingTracer{}
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithTracer(recorder.tracer()),
+	)
+	require.NoError(t, err)
+
+	_, err = client.ListModels(context.Background())
+	require.Error(t, err)
+
+	require.Len(t, recorder.spans, 1)
+	span := recorder.spans[0]
+	assert.Equal(t, "/models", span.name)
+	assert.True(t, span.ended)
+	assert.Error(t, span.err)
+	assert.Equal(t, "authentication", span.attrs[deepseek.AttrErrorType])
+}
+
+func TestTracingServerAfterFailover(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(`{"message":"bad gateway"}`))
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"object":"list","data":[]}`))
+	}))
+	defer backup.Close()
+
+	recorder := &recordingTracer{}
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURLs(primary.URL, backup.URL),
+		deepseek.WithHealthCheckInterval(time.Hour),
+		deepseek.WithTracer(recorder.tracer()),
+	)
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = client.ListModels(context.Background())
+	require.NoError(t, err)
+
+	u, err := url.Parse(backup.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(u.Port())
+	require.NoError(t, err)
+
+	require.Len(t, recorder.spans, 1)
+	span := recorder.spans[0]
+	assert.Equal(t, "127.0.0.1", span.attrs[deepseek.AttrServerAddress])
+	assert.Equal(t, port, span.attrs[deepseek.AttrServerPort])
+}
@@ -0,0 +1,180 @@
+package deepseek_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trustsight-io/deepseek-go"
+)
+
+func chat(client *deepseek.Client) error {
+	_, err := client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	})
+	return err
+}
+
+func certPEM(der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+// clientCertificate writes a self-signed client certificate and its key, and
+// returns their paths and the certificate
+func clientCertificate(t *testing.T) (string, string, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "deepseek-go test client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := writeFile(t, "client.pem", certPEM(der))
+	keyFile := writeFile(t, "client-key.pem", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})))
+	return certFile, keyFile, cert
+}
+
+func TestWithProxy(t *testing.T) {
+	var target string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+		if r.Header.Get("X-Slow") != "" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer proxy.Close()
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL("http://api.deepseek.invalid"),
+		deepseek.WithProxy(proxy.URL),
+		deepseek.WithMaxRetries(0),
+	)
+	require.NoError(t, err)
+	require.NoError(t, chat(client))
+	assert.Equal(t, "http://api.deepseek.invalid/chat/completions", target)
+
+	// The timeout of the HTTP client is kept
+	client, err = deepseek.NewClient("test-key",
+		deepseek.WithBaseURL("http://api.deepseek.invalid"),
+		deepseek.WithTimeout(50*time.Millisecond),
+		deepseek.WithProxy(proxy.URL),
+		deepseek.WithMaxRetries(0),
+	)
+	require.NoError(t, err)
+	_, err = client.CreateChatCompletion(context.Background(), &deepseek.ChatCompletionRequest{
+		Messages: []deepseek.Message{{Role: deepseek.RoleUser, Content: "hi"}},
+	}, deepseek.WithRequestHeader("X-Slow", "1"))
+	assert.Error(t, err)
+}
+
+func TestWithProxyInvalid(t *testing.T) {
+	for _, proxyURL := range []string{"ftp://proxy:21", "http://", "::"} {
+		_, err := deepseek.NewClient("test-key", deepseek.WithProxy(proxyURL))
+		assert.Error(t, err, proxyURL)
+	}
+
+	_, err := deepseek.NewClient("test-key",
+		deepseek.WithHTTPClient(&http.Client{Transport: roundTripperFunc(http.DefaultTransport.RoundTrip)}),
+		deepseek.WithProxy("http://proxy:8080"),
+	)
+	assert.ErrorContains(t, err, "*http.Transport")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	defer server.Close()
+
+	client, err := deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL), deepseek.WithMaxRetries(0))
+	require.NoError(t, err)
+	assert.Error(t, chat(client), "the test CA is not trusted by default")
+
+	bundle := writeFile(t, "ca.pem", certPEM(server.Certificate().Raw))
+	client, err = deepseek.NewClient("test-key", deepseek.WithBaseURL(server.URL), deepseek.WithCABundle(bundle))
+	require.NoError(t, err)
+	assert.NoError(t, chat(client))
+
+	_, err = deepseek.NewClient("test-key", deepseek.WithCABundle(bundle+".missing"))
+	assert.ErrorContains(t, err, "CA bundle")
+	_, err = deepseek.NewClient("test-key", deepseek.WithCACertPEM([]byte("not a certificate")))
+	assert.ErrorContains(t, err, "no valid certificates")
+}
+
+func TestWithClientCertificate(t *testing.T) {
+	certFile, keyFile, cert := clientCertificate(t)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(cert)
+
+	var peer string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		peer = r.TLS.PeerCertificates[0].Subject.CommonName
+		_, _ = w.Write([]byte(`{"choices":[]}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+	bundle := writeFile(t, "ca.pem", certPEM(server.Certificate().Raw))
+
+	client, err := deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithCABundle(bundle),
+		deepseek.WithMaxRetries(0),
+	)
+	require.NoError(t, err)
+	assert.Error(t, chat(client), "the server requires a client certificate")
+
+	client, err = deepseek.NewClient("test-key",
+		deepseek.WithBaseURL(server.URL),
+		deepseek.WithCABundle(bundle),
+		deepseek.WithClientCertificate(certFile, keyFile),
+	)
+	require.NoError(t, err)
+	require.NoError(t, chat(client))
+	assert.Equal(t, "deepseek-go test client", peer)
+
+	_, err = deepseek.NewClient("test-key", deepseek.WithClientCertificate(keyFile, certFile))
+	assert.ErrorContains(t, err, "client certificate")
+}
+
+func TestProfileTLSOptions(t *testing.T) {
+	_, err := deepseek.Profile{ClientCert: "client.pem"}.Options()
+	assert.ErrorContains(t, err, "client_cert and client_key")
+
+	certFile, keyFile, _ := clientCertificate(t)
+	opts, err := deepseek.Profile{ClientCert: certFile, ClientKey: keyFile}.Options()
+	require.NoError(t, err)
+	_, err = deepseek.NewClient("test-key", opts...)
+	assert.NoError(t, err)
+}